@@ -18,6 +18,7 @@ import (
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
 )
@@ -158,23 +159,11 @@ func storeMerkleTreeWithRetry(cfg *config.Config, contractAddress string, merkle
 	}
 	defer layerEdgeClient.Close()
 
-	// Your private key
-	privateKeyStr := cfg.LayerEdgeRPC.PrivateKey
-	// Remove 0x prefix if present, as crypto.HexToECDSA expects hex without prefix
-	privateKeyStr = strings.TrimPrefix(privateKeyStr, "0x")
-	privateKey, err := crypto.HexToECDSA(privateKeyStr)
+	privateKey, fromAddress, err := layerEdgeAccount(cfg)
 	if err != nil {
-		return nil, fmt.Errorf("error parsing private key: %w", err)
+		return nil, err
 	}
 
-	// Get public address
-	publicKey := privateKey.Public()
-	publicKeyECDSA, ok := publicKey.(*ecdsa.PublicKey)
-	if !ok {
-		return nil, fmt.Errorf("cannot assert type: publicKey is not of type *ecdsa.PublicKey")
-	}
-	fromAddress := crypto.PubkeyToAddress(*publicKeyECDSA)
-
 	// Get nonce with timeout
 	nonceCtx, nonceCancel := context.WithTimeout(ctx, 10*time.Second)
 	nonce, err := layerEdgeClient.PendingNonceAt(nonceCtx, fromAddress)
@@ -208,54 +197,14 @@ func storeMerkleTreeWithRetry(cfg *config.Config, contractAddress string, merkle
 		return nil, fmt.Errorf("error creating merkleTreeStorageContract: %w", err)
 	}
 
-	// Parse merkle root string into [32]byte
-	// Expected format: "0xhash" or "hash" (will add 0x prefix if not present)
-	merkleRootStr := strings.TrimSpace(merkle_root)
-	if !strings.HasPrefix(merkleRootStr, "0x") {
-		merkleRootStr = "0x" + merkleRootStr
-	}
-	merkleRootHash := common.HexToHash(merkleRootStr)
-
-	// Parse leaves string into array of bytes
-	// Expected format: plain strings that will be converted to bytes
-	var leafHashes [][32]byte
-
-	for _, leafStr := range leaves {
-		leafStr = strings.TrimSpace(leafStr)
-		if leafStr == "" {
-			continue
-		}
-
-		// Convert plain string to bytes
-		leafHashes = append(leafHashes, common.HexToHash(leafStr))
-	}
-
-	// Prepare call data for gas estimation
-	// Use the ABI from the contracts package
-	storeTreeABI, err := abi.JSON(strings.NewReader(contracts.MerkleTreeStorageABI))
-	if err != nil {
-		return nil, fmt.Errorf("error parsing ABI: %w", err)
-	}
-	storeTreeData, err := storeTreeABI.Pack("storeTree", merkleRootHash, leafHashes)
-	if err != nil {
-		return nil, fmt.Errorf("error packing storeTree data for gas estimation: %w", err)
-	}
-
-	callMsg := ethereum.CallMsg{
-		From:     fromAddress,
-		To:       &contractAddr,
-		Gas:      0,
-		GasPrice: gasPrice,
-		Value:    big.NewInt(0),
-		Data:     storeTreeData,
-	}
+	merkleRootHash, leafHashes := storeTreeArgs(merkle_root, leaves)
 
 	// Estimate gas with timeout
 	gasEstimateCtx, gasEstimateCancel := context.WithTimeout(ctx, 15*time.Second)
-	estimatedGas, err := layerEdgeClient.EstimateGas(gasEstimateCtx, callMsg)
+	estimatedGas, err := estimateStoreTreeGas(gasEstimateCtx, layerEdgeClient, fromAddress, contractAddr, gasPrice, merkleRootHash, leafHashes)
 	gasEstimateCancel()
 	if err != nil {
-		return nil, fmt.Errorf("error estimating gas: %w", err)
+		return nil, err
 	}
 
 	auth.GasLimit = estimatedGas + 10000 // gas limit with buffer
@@ -278,6 +227,52 @@ func storeMerkleTreeWithRetry(cfg *config.Config, contractAddress string, merkle
 		return nil, fmt.Errorf("error waiting for transaction to be mined: %w", err)
 	}
 
+	return txDataFromReceipt(cfg, fromAddress, contractAddress, receipt), nil
+}
+
+// StoredTreeTx returns the TxData of the storeTree transaction that stored
+// merkle_root, found through its TreeCreated event. It recovers the
+// transaction of a root that was stored without being recorded.
+func StoredTreeTx(cfg *config.Config, contractAddress string, merkle_root string) (*TxData, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	layerEdgeClient, err := ethclient.DialContext(ctx, cfg.LayerEdgeRPC.HTTP)
+	if err != nil {
+		return nil, fmt.Errorf("error creating layerEdgeClient: %w", err)
+	}
+	defer layerEdgeClient.Close()
+
+	filterer, err := contracts.NewMerkleTreeStorageFilterer(common.HexToAddress(contractAddress), layerEdgeClient)
+	if err != nil {
+		return nil, fmt.Errorf("error creating merkleTreeStorageFilterer: %w", err)
+	}
+
+	merkleRootHash, _ := storeTreeArgs(merkle_root, nil)
+	events, err := filterer.FilterTreeCreated(&bind.FilterOpts{Context: ctx}, [][32]byte{merkleRootHash}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error filtering TreeCreated events: %w", err)
+	}
+	defer events.Close()
+
+	if !events.Next() {
+		if err := events.Error(); err != nil {
+			return nil, fmt.Errorf("error reading TreeCreated events: %w", err)
+		}
+		return nil, fmt.Errorf("no TreeCreated event for merkle root %s", merkle_root)
+	}
+	event := events.Event
+
+	receipt, err := layerEdgeClient.TransactionReceipt(ctx, event.Raw.TxHash)
+	if err != nil {
+		return nil, fmt.Errorf("error getting receipt of %s: %w", event.Raw.TxHash.Hex(), err)
+	}
+
+	return txDataFromReceipt(cfg, event.Owner, contractAddress, receipt), nil
+}
+
+// txDataFromReceipt describes a mined storeTree transaction sent by from
+func txDataFromReceipt(cfg *config.Config, from common.Address, contractAddress string, receipt *types.Receipt) *TxData {
 	TransactionFee := new(big.Int).Mul(big.NewInt(int64(receipt.GasUsed)), receipt.EffectiveGasPrice)
 	TransactionFee18Decimals := utils.FormatAmount(TransactionFee, 18, 18)
 
@@ -285,14 +280,125 @@ func storeMerkleTreeWithRetry(cfg *config.Config, contractAddress string, merkle
 
 	return &TxData{
 		Success:         receipt.Status == 1,
-		From:            fromAddress.Hex(),
+		From:            from.Hex(),
 		To:              contractAddress,
 		Amount:          fmt.Sprintf("%.18f", EdgenPrice*TransactionFee18Decimals),
-		TransactionHash: tx.Hash().Hex(),
+		TransactionHash: receipt.TxHash.Hex(),
 		TransactionFee:  fmt.Sprintf("%.18f", TransactionFee18Decimals),
 		EdgenPrice:      fmt.Sprintf("%.18f", EdgenPrice),
 		Memo:            "",
 		BlockHeight:     receipt.BlockNumber.String(),
 		GasUsed:         strconv.FormatUint(receipt.GasUsed, 10),
-	}, nil
+	}
+}
+
+// EstimateStoreTreeGas estimates the gas of storing merkle_root and leaves
+// with storeTree, without sending a transaction
+func EstimateStoreTreeGas(cfg *config.Config, contractAddress string, merkle_root string, leaves []string) (uint64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	layerEdgeClient, err := ethclient.DialContext(ctx, cfg.LayerEdgeRPC.HTTP)
+	if err != nil {
+		return 0, fmt.Errorf("error creating layerEdgeClient: %w", err)
+	}
+	defer layerEdgeClient.Close()
+
+	_, fromAddress, err := layerEdgeAccount(cfg)
+	if err != nil {
+		return 0, err
+	}
+
+	merkleRootHash, leafHashes := storeTreeArgs(merkle_root, leaves)
+	return estimateStoreTreeGas(ctx, layerEdgeClient, fromAddress, common.HexToAddress(contractAddress), nil, merkleRootHash, leafHashes)
+}
+
+// layerEdgeAccount parses layer-edge-rpc.private-key and returns it with its address
+func layerEdgeAccount(cfg *config.Config) (*ecdsa.PrivateKey, common.Address, error) {
+	// Remove 0x prefix if present, as crypto.HexToECDSA expects hex without prefix
+	privateKeyStr := strings.TrimPrefix(cfg.LayerEdgeRPC.PrivateKey, "0x")
+	privateKey, err := crypto.HexToECDSA(privateKeyStr)
+	if err != nil {
+		return nil, common.Address{}, fmt.Errorf("error parsing private key: %w", err)
+	}
+
+	// Get public address
+	publicKey := privateKey.Public()
+	publicKeyECDSA, ok := publicKey.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, common.Address{}, fmt.Errorf("cannot assert type: publicKey is not of type *ecdsa.PublicKey")
+	}
+	return privateKey, crypto.PubkeyToAddress(*publicKeyECDSA), nil
+}
+
+// storeTreeArgs parses the merkle root ("0xhash" or "hash") and hex leaves
+// into storeTree arguments, skipping empty leaves
+func storeTreeArgs(merkle_root string, leaves []string) (common.Hash, [][32]byte) {
+	merkleRootStr := strings.TrimSpace(merkle_root)
+	if !strings.HasPrefix(merkleRootStr, "0x") {
+		merkleRootStr = "0x" + merkleRootStr
+	}
+
+	var leafHashes [][32]byte
+	for _, leafStr := range leaves {
+		leafStr = strings.TrimSpace(leafStr)
+		if leafStr == "" {
+			continue
+		}
+		leafHashes = append(leafHashes, common.HexToHash(leafStr))
+	}
+	return common.HexToHash(merkleRootStr), leafHashes
+}
+
+func estimateStoreTreeGas(ctx context.Context, client *ethclient.Client, from common.Address, contractAddr common.Address, gasPrice *big.Int, merkleRootHash common.Hash, leafHashes [][32]byte) (uint64, error) {
+	// Use the ABI from the contracts package
+	storeTreeABI, err := abi.JSON(strings.NewReader(contracts.MerkleTreeStorageABI))
+	if err != nil {
+		return 0, fmt.Errorf("error parsing ABI: %w", err)
+	}
+	storeTreeData, err := storeTreeABI.Pack("storeTree", merkleRootHash, leafHashes)
+	if err != nil {
+		return 0, fmt.Errorf("error packing storeTree data for gas estimation: %w", err)
+	}
+
+	estimatedGas, err := client.EstimateGas(ctx, ethereum.CallMsg{
+		From:     from,
+		To:       &contractAddr,
+		GasPrice: gasPrice,
+		Value:    big.NewInt(0),
+		Data:     storeTreeData,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("error estimating gas: %w", err)
+	}
+	return estimatedGas, nil
+}
+
+// TreeExists checks whether merkle_root has been stored in the given MerkleTreeStorage contract
+func TreeExists(cfg *config.Config, contractAddress string, merkle_root string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	layerEdgeClient, err := ethclient.DialContext(ctx, cfg.LayerEdgeRPC.HTTP)
+	if err != nil {
+		return false, fmt.Errorf("error creating layerEdgeClient: %w", err)
+	}
+	defer layerEdgeClient.Close()
+
+	caller, err := contracts.NewMerkleTreeStorageCaller(common.HexToAddress(contractAddress), layerEdgeClient)
+	if err != nil {
+		return false, fmt.Errorf("error creating merkleTreeStorageCaller: %w", err)
+	}
+
+	merkleRootStr := strings.TrimSpace(merkle_root)
+	if !strings.HasPrefix(merkleRootStr, "0x") {
+		merkleRootStr = "0x" + merkleRootStr
+	}
+
+	exists, err := caller.TreeExists(&bind.CallOpts{Context: ctx}, common.HexToHash(merkleRootStr))
+	if err != nil {
+		return false, fmt.Errorf("error in treeExists contract call: %w", err)
+	}
+
+	return exists, nil
 }
@@ -11,20 +11,169 @@ import (
 	"gopkg.in/yaml.v2"
 )
 
+// Submitter is a key authorised to submit proofs, see SubmitterAuth
+type Submitter struct {
+	Name       string   `yaml:"name"`
+	Scheme     string   `yaml:"scheme"`     // secp256k1 | ed25519
+	PublicKey  string   `yaml:"public-key"` // hex, compressed or uncompressed for secp256k1
+	Namespaces []string `yaml:"namespaces"` // empty allows every namespace
+}
+
+// RateLimitRule limits one namespace or submitter. Rate is in submissions
+// per second; a zero Rate or WindowQuota leaves that limit off.
+type RateLimitRule struct {
+	Name        string  `yaml:"name"` // namespace or submitter name, unused for the default rule
+	Rate        float64 `yaml:"rate"`
+	Burst       int     `yaml:"burst"`
+	WindowQuota int     `yaml:"window-quota"` // leaves per aggregation window
+}
+
+// ProofType registers a proof validator under Name
+type ProofType struct {
+	Name         string `yaml:"name"`
+	Kind         string `yaml:"kind"`          // abi | groth16 | plonk | state-root
+	ABI          string `yaml:"abi"`           // JSON argument list, for abi
+	Curve        string `yaml:"curve"`         // for groth16 and plonk, defaults to bn254
+	VerifyingKey string `yaml:"verifying-key"` // gnark verifying key file, for groth16 and plonk
+}
+
+// NamespaceKey is the Groth16 verifying key of a namespace
+type NamespaceKey struct {
+	Namespace    string `yaml:"namespace"`
+	VerifyingKey string `yaml:"verifying-key"` // gnark BN254 verifying key file
+}
+
 type Config struct {
 	ProtocolId string `yaml:"protocol-id"`
 
 	ZmqEndpointDataBlock string `yaml:"zmq-endpoint-data-block"`
+	ZmqEndpointRawBlock  string `yaml:"zmq-endpoint-raw-block"`
+	ZmqEndpointHashBlock string `yaml:"zmq-endpoint-hash-block"`
 
 	BtcEndpoint      string `yaml:"bitcoin-endpoint"`
 	User             string `yaml:"bitcoin-user"`
 	Auth             string `yaml:"bitcoin-auth"`
 	WalletPassphrase string `yaml:"bitcoin-wallet-passphrase"`
 
+	SubmitterAuth struct {
+		Enabled    bool        `yaml:"enabled"`
+		Registry   string      `yaml:"registry"` // config | postgres | both
+		Submitters []Submitter `yaml:"submitters"`
+	} `yaml:"submitter-auth"`
+
+	ProofValidation struct {
+		Enabled     bool        `yaml:"enabled"`
+		DefaultType string      `yaml:"default-type"` // for submissions without a proof type; empty rejects them
+		Types       []ProofType `yaml:"types"`
+	} `yaml:"proof-validation"`
+
+	Groth16Verification struct {
+		Enabled         bool           `yaml:"enabled"`
+		AllowUnverified bool           `yaml:"allow-unverified"` // store proofs of namespaces without a key as unverified instead of rejecting them
+		Keys            []NamespaceKey `yaml:"keys"`
+	} `yaml:"groth16-verification"`
+
+	RateLimit struct {
+		Enabled   bool            `yaml:"enabled"`
+		Key       string          `yaml:"key"` // namespace | submitter
+		Default   RateLimitRule   `yaml:"default"`
+		Overrides []RateLimitRule `yaml:"overrides"`
+	} `yaml:"rate-limit"`
+
+	DataBlockCurve struct {
+		Enabled          bool     `yaml:"enabled"`
+		ServerSecretKey  string   `yaml:"server-secret-key"`  // Z85, the public key is derived from it
+		ClientPublicKeys []string `yaml:"client-public-keys"` // Z85, empty accepts any client key
+	} `yaml:"data-block-curve"`
+
+	API struct {
+		Enabled              bool   `yaml:"enabled"`
+		ListenAddress        string `yaml:"listen-address"`
+		SubmitTimeoutSeconds int    `yaml:"submit-timeout-seconds"` // how long POST /v1/proofs waits for the writer
+		MaxBodyBytes         int64  `yaml:"max-body-bytes"`
+	} `yaml:"api"`
+
+	GRPC struct {
+		Enabled                  bool   `yaml:"enabled"`
+		ListenAddress            string `yaml:"listen-address"`
+		SubmitTimeoutSeconds     int    `yaml:"submit-timeout-seconds"`      // how long SubmitProof/SubmitBatch wait for the writer
+		WatchPollIntervalSeconds int    `yaml:"watch-poll-interval-seconds"` // how often WatchProof checks for a stage change
+	} `yaml:"grpc"`
+
 	WriteIntervalBlock             int `yaml:"write-interval-blocks"`
 	WriteIntervalSeconds           int `yaml:"write-interval-seconds"`
 	SuperProofWriteIntervalSeconds int `yaml:"super-proof-write-interval-seconds"`
 
+	Sealing struct {
+		MaxLeaves     int    `yaml:"max-leaves"`      // defaults to write-interval-blocks
+		MaxBytes      int    `yaml:"max-bytes"`       // total proof bytes, 0 for unlimited
+		MaxGas        uint64 `yaml:"max-gas"`         // estimated storeTree gas, 0 for unlimited
+		GasCheckEvery int    `yaml:"gas-check-every"` // leaves between gas estimates
+	} `yaml:"sealing"`
+
+	Pipeline struct {
+		ValidateWorkers int `yaml:"validate-workers"` // proofs authenticated, deduplicated and verified concurrently
+		PersistWorkers  int `yaml:"persist-workers"`  // proofs inserted into pending_proofs concurrently
+		QueueSize       int `yaml:"queue-size"`       // capacity of the queues between receive, validate, persist and seal
+		PublishQueue    int `yaml:"publish-queue"`    // sealed windows waiting to be stored on LayerEdge
+		PublishAttempts int `yaml:"publish-attempts"` // failed stores before a window is set aside until the next start
+	} `yaml:"pipeline"`
+
+	Reader struct {
+		Enabled             bool   `yaml:"enabled"`
+		Mode                string `yaml:"mode"` // zmq | poll
+		PollIntervalSeconds int    `yaml:"poll-interval-seconds"`
+		StartHeight         int64  `yaml:"start-height"` // 0 starts at the current tip
+	} `yaml:"reader"`
+
+	ConfirmationTracker struct {
+		RequiredConfirmations int64 `yaml:"required-confirmations"`
+		PollIntervalSeconds   int   `yaml:"poll-interval-seconds"`
+	} `yaml:"confirmation-tracker"`
+
+	FeeEstimation struct {
+		ConfTarget      int     `yaml:"conf-target"`       // blocks, passed to estimatesmartfee
+		Mode            string  `yaml:"mode"`              // economical | conservative
+		MinFeeRate      float64 `yaml:"min-fee-rate"`      // sat/vB
+		MaxFeeRate      float64 `yaml:"max-fee-rate"`      // sat/vB
+		FallbackFeeRate float64 `yaml:"fallback-fee-rate"` // sat/vB, used when the node has no estimate
+	} `yaml:"fee-estimation"`
+
+	CoinSelection struct {
+		Strategy      string `yaml:"strategy"` // branch-and-bound | largest-first | privacy
+		Consolidation struct {
+			Enabled             bool    `yaml:"enabled"`
+			PollIntervalSeconds int     `yaml:"poll-interval-seconds"`
+			MinUTXOs            int     `yaml:"min-utxos"`    // consolidate once the wallet holds this many UTXOs
+			MaxInputs           int     `yaml:"max-inputs"`   // per consolidation transaction
+			MaxFeeRate          float64 `yaml:"max-fee-rate"` // sat/vB, only consolidate when fees are low
+		} `yaml:"consolidation"`
+	} `yaml:"coin-selection"`
+
+	OPReturn struct {
+		MaxDataSize    int  `yaml:"max-data-size"`   // bytes, bitcoind's -datacarriersize
+		SplitOversized bool `yaml:"split-oversized"` // split larger payloads across linked anchors instead of refusing them
+	} `yaml:"op-return"`
+
+	AnchorMode struct {
+		SuperProof      string `yaml:"super-proof"`       // op-return | inscription, for SuperProofCronJob
+		SuperProofRetry string `yaml:"super-proof-retry"` // op-return | inscription, for NonBTCTxSuperProofCronJob
+	} `yaml:"anchor-mode"`
+
+	Inscription struct {
+		PrivateKey  string `yaml:"private-key"`  // hex secp256k1 key for the tapscript, required for inscription mode
+		PostageSats int64  `yaml:"postage-sats"` // value of the reveal output returned to the wallet
+	} `yaml:"inscription"`
+
+	FeeBumper struct {
+		Enabled             bool    `yaml:"enabled"`
+		PollIntervalSeconds int     `yaml:"poll-interval-seconds"`
+		StuckAfterMinutes   int     `yaml:"stuck-after-minutes"`
+		BumpMultiplier      float64 `yaml:"bump-multiplier"`
+		MaxFeeRate          float64 `yaml:"max-fee-rate"` // sat/vB
+		MaxFeeSats          int64   `yaml:"max-fee-sats"` // total fee budget per anchor, including any CPFP child
+	} `yaml:"fee-bumper"`
+
 	LayerEdgeRPC struct {
 		ChainID                   int64  `yaml:"chain-id"`
 		HTTP                      string `yaml:"http"`
@@ -34,6 +183,15 @@ type Config struct {
 	} `yaml:"layer-edge-rpc"`
 
 	MerkleTreeGeneratorServer string `yaml:"merkle-tree-generator-server"`
+	MerkleTreeMode            string `yaml:"merkle-tree-mode"` // native | remote | cross-check
+
+	AggregationBackend string `yaml:"aggregation-backend"` // merkle | snark
+	SnarkAggregation   struct {
+		InnerVerifyingKey string `yaml:"inner-verifying-key"` // BN254 Groth16 key of the circuit whose proofs are aggregated
+		BatchSize         int    `yaml:"batch-size"`          // proofs verified by one aggregated proof
+		ProvingKey        string `yaml:"proving-key"`         // keys of the aggregation circuit, see tools/snark_setup.go
+		VerifyingKey      string `yaml:"verifying-key"`
+	} `yaml:"snark-aggregation"`
 
 	PostgresConnectionURI string `yaml:"postgres-connection-uri"`
 
@@ -114,8 +272,36 @@ func validateConfig(cfg *Config) {
 		log.Fatal("Postgres Connection URI is required in config file")
 	}
 
-	if cfg.MerkleTreeGeneratorServer == "" {
-		log.Fatal("Merkle Tree Generator Server is required")
+	if cfg.MerkleTreeMode == "" {
+		cfg.MerkleTreeMode = "native" // defaults to the in-process builder
+	}
+
+	switch cfg.MerkleTreeMode {
+	case "native":
+	case "remote", "cross-check":
+		if cfg.MerkleTreeGeneratorServer == "" {
+			log.Fatalf("Merkle Tree Generator Server is required for merkle-tree-mode %q", cfg.MerkleTreeMode)
+		}
+	default:
+		log.Fatalf("Unknown merkle-tree-mode %q", cfg.MerkleTreeMode)
+	}
+
+	if cfg.AggregationBackend == "" {
+		cfg.AggregationBackend = "merkle"
+	}
+
+	switch cfg.AggregationBackend {
+	case "merkle":
+	case "snark":
+		if cfg.SnarkAggregation.InnerVerifyingKey == "" || cfg.SnarkAggregation.ProvingKey == "" || cfg.SnarkAggregation.VerifyingKey == "" {
+			log.Fatal("snark-aggregation needs an inner-verifying-key, a proving-key and a verifying-key")
+		}
+	default:
+		log.Fatalf("Unknown aggregation-backend %q", cfg.AggregationBackend)
+	}
+
+	if cfg.SnarkAggregation.BatchSize == 0 {
+		cfg.SnarkAggregation.BatchSize = 4
 	}
 
 	if cfg.Auth == "" {
@@ -155,6 +341,228 @@ func validateConfig(cfg *Config) {
 		cfg.WriteIntervalSeconds = 600 // defaults to 10 min
 	}
 
+	if cfg.Sealing.MaxLeaves == 0 {
+		cfg.Sealing.MaxLeaves = cfg.WriteIntervalBlock
+	}
+
+	// A snark aggregate proves at most one batch of proofs
+	if cfg.AggregationBackend == "snark" && cfg.Sealing.MaxLeaves > cfg.SnarkAggregation.BatchSize {
+		log.Printf("Lowering sealing max-leaves from %d to the snark batch-size %d", cfg.Sealing.MaxLeaves, cfg.SnarkAggregation.BatchSize)
+		cfg.Sealing.MaxLeaves = cfg.SnarkAggregation.BatchSize
+	}
+
+	if cfg.Sealing.GasCheckEvery == 0 {
+		cfg.Sealing.GasCheckEvery = 16
+	}
+
+	if cfg.Pipeline.ValidateWorkers == 0 {
+		cfg.Pipeline.ValidateWorkers = 4
+	}
+
+	if cfg.Pipeline.PersistWorkers == 0 {
+		cfg.Pipeline.PersistWorkers = 4
+	}
+
+	if cfg.Pipeline.QueueSize == 0 {
+		cfg.Pipeline.QueueSize = 64
+	}
+
+	if cfg.Pipeline.PublishQueue == 0 {
+		cfg.Pipeline.PublishQueue = 4
+	}
+
+	if cfg.Pipeline.PublishAttempts == 0 {
+		cfg.Pipeline.PublishAttempts = 10
+	}
+
+	if cfg.SubmitterAuth.Registry == "" {
+		cfg.SubmitterAuth.Registry = "config"
+	}
+
+	switch cfg.SubmitterAuth.Registry {
+	case "config", "postgres", "both":
+	default:
+		log.Fatalf("Unknown submitter-auth registry %q", cfg.SubmitterAuth.Registry)
+	}
+
+	for i, proofType := range cfg.ProofValidation.Types {
+		switch proofType.Kind {
+		case "abi", "state-root":
+		case "groth16", "plonk":
+			if proofType.Curve == "" {
+				cfg.ProofValidation.Types[i].Curve = "bn254"
+			}
+			if proofType.VerifyingKey == "" {
+				log.Fatalf("proof-validation type %q needs a verifying-key", proofType.Name)
+			}
+		default:
+			log.Fatalf("Unknown proof-validation kind %q for type %q", proofType.Kind, proofType.Name)
+		}
+	}
+
+	for _, key := range cfg.Groth16Verification.Keys {
+		if key.Namespace == "" || key.VerifyingKey == "" {
+			log.Fatal("groth16-verification keys need a namespace and a verifying-key")
+		}
+	}
+	if cfg.Groth16Verification.Enabled && !cfg.Groth16Verification.AllowUnverified && len(cfg.Groth16Verification.Keys) == 0 {
+		log.Fatal("groth16-verification rejects every proof when no keys are configured, add keys or set allow-unverified")
+	}
+
+	if cfg.RateLimit.Key == "" {
+		cfg.RateLimit.Key = "namespace"
+	}
+
+	switch cfg.RateLimit.Key {
+	case "namespace":
+	case "submitter":
+		if cfg.RateLimit.Enabled && !cfg.SubmitterAuth.Enabled {
+			log.Fatal("rate-limit.key submitter requires submitter-auth to be enabled")
+		}
+	default:
+		log.Fatalf("Unknown rate-limit key %q", cfg.RateLimit.Key)
+	}
+
+	if cfg.DataBlockCurve.Enabled && cfg.DataBlockCurve.ServerSecretKey == "" {
+		log.Fatal("data-block-curve.server-secret-key is required when CURVE is enabled")
+	}
+
+	if cfg.API.ListenAddress == "" {
+		cfg.API.ListenAddress = ":8080"
+	}
+
+	if cfg.API.SubmitTimeoutSeconds == 0 {
+		cfg.API.SubmitTimeoutSeconds = 30
+	}
+
+	if cfg.API.MaxBodyBytes == 0 {
+		cfg.API.MaxBodyBytes = 1 << 20 // 1 MiB
+	}
+
+	if cfg.GRPC.ListenAddress == "" {
+		cfg.GRPC.ListenAddress = ":9090"
+	}
+
+	if cfg.GRPC.SubmitTimeoutSeconds == 0 {
+		cfg.GRPC.SubmitTimeoutSeconds = 30
+	}
+
+	if cfg.GRPC.WatchPollIntervalSeconds == 0 {
+		cfg.GRPC.WatchPollIntervalSeconds = 10
+	}
+
+	if cfg.Reader.Mode == "" {
+		cfg.Reader.Mode = "zmq"
+		if cfg.ZmqEndpointRawBlock == "" && cfg.ZmqEndpointHashBlock == "" {
+			cfg.Reader.Mode = "poll"
+		}
+	}
+
+	if cfg.Reader.PollIntervalSeconds == 0 {
+		cfg.Reader.PollIntervalSeconds = 30 // defaults to 30 seconds
+	}
+
+	if cfg.ConfirmationTracker.RequiredConfirmations == 0 {
+		cfg.ConfirmationTracker.RequiredConfirmations = 6 // defaults to 6 blocks
+	}
+
+	if cfg.ConfirmationTracker.PollIntervalSeconds == 0 {
+		cfg.ConfirmationTracker.PollIntervalSeconds = 300 // defaults to 5 min
+	}
+
+	if cfg.FeeEstimation.ConfTarget == 0 {
+		cfg.FeeEstimation.ConfTarget = 6 // defaults to ~1 hour
+	}
+
+	if cfg.FeeEstimation.Mode == "" {
+		cfg.FeeEstimation.Mode = "economical"
+	}
+
+	if cfg.FeeEstimation.Mode != "economical" && cfg.FeeEstimation.Mode != "conservative" {
+		log.Fatalf("Unknown fee-estimation mode %q", cfg.FeeEstimation.Mode)
+	}
+
+	if cfg.FeeEstimation.MinFeeRate == 0 {
+		cfg.FeeEstimation.MinFeeRate = 1 // sat/vB, default min relay fee
+	}
+
+	if cfg.FeeEstimation.MaxFeeRate == 0 {
+		cfg.FeeEstimation.MaxFeeRate = 100 // sat/vB
+	}
+
+	if cfg.FeeEstimation.FallbackFeeRate == 0 {
+		cfg.FeeEstimation.FallbackFeeRate = 5 // sat/vB
+	}
+
+	if cfg.CoinSelection.Strategy == "" {
+		cfg.CoinSelection.Strategy = "branch-and-bound" // falls back to largest-first when change is needed
+	}
+
+	switch cfg.CoinSelection.Strategy {
+	case "branch-and-bound", "largest-first", "privacy":
+	default:
+		log.Fatalf("Unknown coin-selection strategy %q", cfg.CoinSelection.Strategy)
+	}
+
+	if cfg.CoinSelection.Consolidation.PollIntervalSeconds == 0 {
+		cfg.CoinSelection.Consolidation.PollIntervalSeconds = 3600 // defaults to 1 hour
+	}
+
+	if cfg.CoinSelection.Consolidation.MinUTXOs == 0 {
+		cfg.CoinSelection.Consolidation.MinUTXOs = 50
+	}
+
+	if cfg.CoinSelection.Consolidation.MaxInputs == 0 {
+		cfg.CoinSelection.Consolidation.MaxInputs = 100
+	}
+
+	if cfg.CoinSelection.Consolidation.MaxFeeRate == 0 {
+		cfg.CoinSelection.Consolidation.MaxFeeRate = 5 // sat/vB
+	}
+
+	if cfg.OPReturn.MaxDataSize == 0 {
+		cfg.OPReturn.MaxDataSize = 80 // default relay policy
+	}
+
+	for _, mode := range []*string{&cfg.AnchorMode.SuperProof, &cfg.AnchorMode.SuperProofRetry} {
+		if *mode == "" {
+			*mode = "op-return"
+		}
+		switch *mode {
+		case "op-return":
+		case "inscription":
+			if cfg.Inscription.PrivateKey == "" {
+				log.Fatal("Inscription PrivateKey is required for inscription anchor mode")
+			}
+		default:
+			log.Fatalf("Unknown anchor-mode %q", *mode)
+		}
+	}
+
+	if cfg.Inscription.PostageSats == 0 {
+		cfg.Inscription.PostageSats = 546 // P2WPKH dust limit
+	}
+
+	if cfg.FeeBumper.PollIntervalSeconds == 0 {
+		cfg.FeeBumper.PollIntervalSeconds = 600 // defaults to 10 min
+	}
+
+	if cfg.FeeBumper.StuckAfterMinutes == 0 {
+		cfg.FeeBumper.StuckAfterMinutes = 60 // defaults to 1 hour
+	}
+
+	if cfg.FeeBumper.BumpMultiplier <= 1 {
+		cfg.FeeBumper.BumpMultiplier = 1.5
+	}
+
+	if cfg.FeeBumper.MaxFeeRate == 0 {
+		cfg.FeeBumper.MaxFeeRate = 200 // sat/vB
+	}
+
+	if cfg.FeeBumper.MaxFeeSats == 0 {
+		cfg.FeeBumper.MaxFeeSats = 100000 // 0.001 BTC
+	}
+
 	if cfg.SuperProofWriteIntervalSeconds == 0 {
 		cfg.SuperProofWriteIntervalSeconds = 84600 // defaults to 24 hours
 	}
@@ -44,11 +44,15 @@ type RetryConfig struct {
 
 type BlockSubscriber struct {
 	channeler      *goczmq.Channeler
+	curve          *CurveChanneler // set instead of channeler by CurveReplier
 	circuitBreaker *CircuitBreaker
 	retryConfig    *RetryConfig
 	ctx            context.Context
 	cancel         context.CancelFunc
 	mu             sync.RWMutex
+
+	// submitters authorises data socket submissions when submitter-auth is enabled
+	submitters *SubmitterRegistry
 }
 
 // NewBlockSubscriber creates a new BlockSubscriber with circuit breaker and retry mechanisms
@@ -159,9 +163,7 @@ func (subr *BlockSubscriber) Subscribe(endpoint string, filter string) bool {
 		defer subr.mu.Unlock()
 
 		// Clean up existing channeler if any
-		if subr.channeler != nil {
-			subr.channeler.Destroy()
-		}
+		subr.destroyChannelers()
 
 		subr.channeler = goczmq.NewSubChanneler(endpoint, filter)
 		if subr.channeler == nil {
@@ -181,9 +183,7 @@ func (subr *BlockSubscriber) Replier(endpoint string) bool {
 		defer subr.mu.Unlock()
 
 		// Clean up existing channeler if any
-		if subr.channeler != nil {
-			subr.channeler.Destroy()
-		}
+		subr.destroyChannelers()
 
 		subr.channeler = goczmq.NewRepChanneler(endpoint)
 		if subr.channeler == nil {
@@ -195,14 +195,56 @@ func (subr *BlockSubscriber) Replier(endpoint string) bool {
 	}) == nil
 }
 
+// CurveReplier binds endpoint like Replier, but with CURVE encryption and
+// only accepting the given client keys (any client key if empty)
+func (subr *BlockSubscriber) CurveReplier(endpoint string, serverSecretKey string, clientPublicKeys []string) bool {
+	log.Println("CURVE Replier:", endpoint)
+
+	return subr.RetryWithBackoff(func() error {
+		subr.mu.Lock()
+		defer subr.mu.Unlock()
+
+		// Clean up existing channeler if any
+		subr.destroyChannelers()
+
+		curve, err := NewCurveRepChanneler(endpoint, serverSecretKey, clientPublicKeys)
+		if err != nil {
+			return fmt.Errorf("failed to create CURVE reply channeler for endpoint %s: %w", endpoint, err)
+		}
+		subr.curve = curve
+
+		log.Println("Successfully created CURVE reply channeler")
+		return nil
+	}) == nil
+}
+
 func (subr *BlockSubscriber) Reset() {
 	subr.mu.Lock()
 	defer subr.mu.Unlock()
 
+	subr.destroyChannelers()
+}
+
+func (subr *BlockSubscriber) destroyChannelers() {
 	if subr.channeler != nil {
 		subr.channeler.Destroy()
 		subr.channeler = nil
 	}
+	if subr.curve != nil {
+		subr.curve.Destroy()
+		subr.curve = nil
+	}
+}
+
+// channels returns the receive and send channels of the open socket
+func (subr *BlockSubscriber) channels() (<-chan [][]byte, chan<- [][]byte, bool) {
+	if subr.curve != nil {
+		return subr.curve.RecvChan, subr.curve.SendChan, true
+	}
+	if subr.channeler != nil {
+		return subr.channeler.RecvChan, subr.channeler.SendChan, true
+	}
+	return nil, nil, false
 }
 
 // Close gracefully shuts down the BlockSubscriber
@@ -216,13 +258,14 @@ func (subr *BlockSubscriber) GetMessage() (bool, [][]byte) {
 	subr.mu.RLock()
 	defer subr.mu.RUnlock()
 
-	if subr.channeler == nil {
+	recvChan, _, ok := subr.channels()
+	if !ok {
 		log.Println("Channeler is nil, cannot get message")
 		return false, nil
 	}
 
 	select {
-	case msg, ok := <-subr.channeler.RecvChan:
+	case msg, ok := <-recvChan:
 		return ok, msg
 	case <-subr.ctx.Done():
 		log.Println("Context cancelled, stopping message retrieval")
@@ -230,26 +273,63 @@ func (subr *BlockSubscriber) GetMessage() (bool, [][]byte) {
 	}
 }
 
+// SetSubmitterRegistry makes Authenticate require a signature frame from an
+// authorised submitter
+func (subr *BlockSubscriber) SetSubmitterRegistry(registry *SubmitterRegistry) {
+	subr.submitters = registry
+}
+
+// Authenticate checks the signature frame of a submission to namespace and
+// returns its submitter. It accepts everything, with a nil submitter, when no
+// submitter registry is set. Rejections are counted in the error handler
+// metrics.
+func (subr *BlockSubscriber) Authenticate(namespace string, proof []byte, signature []byte) (*Submitter, error) {
+	if subr.submitters == nil {
+		return nil, nil
+	}
+
+	submitter, err := subr.submitters.Verify(namespace, proof, signature)
+	if err != nil {
+		utils.LogAuthError("BlockSubscriber", "Rejected submission", err, map[string]interface{}{
+			"namespace": namespace,
+		})
+		return nil, fmt.Errorf("unauthorised submission: %w", err)
+	}
+
+	log.Printf("Submission to %s signed by %s", namespace, submitter.Name)
+	return submitter, nil
+}
+
 func (subr *BlockSubscriber) Validate(ok bool, msg [][]byte) bool {
+	if err := subr.ValidateMessage(ok, msg); err != nil {
+		log.Println(err)
+		return false
+	}
+	return true
+}
+
+// ValidateMessage is Validate returning the reason a message was rejected
+func (subr *BlockSubscriber) ValidateMessage(ok bool, msg [][]byte) error {
 	// Validate
 	if !ok {
-		log.Println("Failed to receive message")
-		return false
+		return fmt.Errorf("failed to receive message")
 	}
-	if len(msg) != 3 {
-		log.Printf("Received message with unexpected number of parts: expected 3, got %d", len(msg))
-		return false
+	// label, proof, signature and an optional proof type
+	if len(msg) != 3 && len(msg) != 4 {
+		return fmt.Errorf("received message with unexpected number of parts: expected 3 or 4, got %d", len(msg))
+	}
+	if string(msg[0]) == StatusQueryLabel {
+		return fmt.Errorf("label %q is reserved for status queries", StatusQueryLabel)
 	}
 
 	// Additional validation for message content
 	for i, part := range msg {
 		if len(part) == 0 {
-			log.Printf("Message part %d is empty", i)
-			return false
+			return fmt.Errorf("message part %d is empty", i)
 		}
 	}
 
-	return true
+	return nil
 }
 
 func (subr *BlockSubscriber) Process(fn Lambda, msg [][]byte) bool {
@@ -0,0 +1,137 @@
+package da
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"github.com/Layer-Edge/bitcoin-da/models"
+)
+
+// AckVersion is the version of the reply envelope sent to data submitters
+const AckVersion = 1
+
+// AckStatus tells a submitter what happened to its proof
+type AckStatus int
+
+const (
+	AckStatusAccepted  AckStatus = 0 // persisted and queued for the expected window
+	AckStatusDuplicate AckStatus = 1 // already submitted, see ProofID
+	AckStatusRejected  AckStatus = 2 // invalid submission, see Reason; do not retry as is
+	AckStatusFailed    AckStatus = 3 // internal failure, safe to retry
+	AckStatusThrottled AckStatus = 4 // over a rate limit or window quota, retry after RetryAfterSeconds
+	AckStatusUnknown   AckStatus = 5 // taken by the writer without an answer in time, query ProofID before retrying
+)
+
+func (s AckStatus) String() string {
+	switch s {
+	case AckStatusAccepted:
+		return "accepted"
+	case AckStatusDuplicate:
+		return "duplicate"
+	case AckStatusRejected:
+		return "rejected"
+	case AckStatusFailed:
+		return "failed"
+	case AckStatusThrottled:
+		return "throttled"
+	case AckStatusUnknown:
+		return "unknown"
+	default:
+		return fmt.Sprintf("AckStatus(%d)", int(s))
+	}
+}
+
+// AckWindow is the aggregation window a proof is expected to be sealed in
+type AckWindow struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// Ack is the JSON reply envelope sent on the data socket for every request
+type Ack struct {
+	Version    int        `json:"version"`
+	Status     AckStatus  `json:"status"`
+	StatusText string     `json:"status_text"`
+	ProofID    string     `json:"proof_id,omitempty"` // keccak256 leaf hash of the proof
+	Window     *AckWindow `json:"window,omitempty"`
+	Reason     string     `json:"reason,omitempty"`
+
+	AggregateRoot     string `json:"aggregate_root,omitempty"`      // set on duplicates already aggregated
+	RetryAfterSeconds int    `json:"retry_after_seconds,omitempty"` // set on throttled ACKs
+}
+
+// NewAck builds a reply envelope with the given status
+func NewAck(status AckStatus, proofID string, reason string) *Ack {
+	return &Ack{
+		Version:    AckVersion,
+		Status:     status,
+		StatusText: status.String(),
+		ProofID:    proofID,
+		Reason:     reason,
+	}
+}
+
+// NewDuplicateAck points a resubmitted proof to its first submission: the
+// aggregate it was sealed in, or window if it is still pending
+func NewDuplicateAck(existing *models.PendingProof, window *AckWindow) *Ack {
+	if existing.AggregateRoot != nil {
+		ack := NewAck(AckStatusDuplicate, existing.LeafHash, "proof was already aggregated")
+		ack.AggregateRoot = *existing.AggregateRoot
+		return ack
+	}
+
+	ack := NewAck(AckStatusDuplicate, existing.LeafHash, "proof is already queued for aggregation")
+	ack.Window = window
+	return ack
+}
+
+// NewThrottledAck builds a throttled reply telling the submitter when to retry
+func NewThrottledAck(proofID string, reason string, retryAfter time.Duration) *Ack {
+	ack := NewAck(AckStatusThrottled, proofID, reason)
+	ack.RetryAfterSeconds = int(math.Ceil(retryAfter.Seconds()))
+	if ack.RetryAfterSeconds < 1 {
+		ack.RetryAfterSeconds = 1
+	}
+	return ack
+}
+
+// ParseAck decodes a reply envelope
+func ParseAck(data []byte) (*Ack, error) {
+	var ack Ack
+	if err := json.Unmarshal(data, &ack); err != nil {
+		return nil, err
+	}
+	return &ack, nil
+}
+
+// SendAck replies to the current request on the data socket
+func (subr *BlockSubscriber) SendAck(ack *Ack) {
+	subr.sendReply("ACK", ack)
+}
+
+// sendReply marshals v as JSON and sends it on the data socket
+func (subr *BlockSubscriber) sendReply(kind string, v interface{}) {
+	reply, err := json.Marshal(v)
+	if err != nil {
+		log.Printf("Failed to marshal %s: %v", kind, err)
+		return
+	}
+
+	subr.mu.RLock()
+	_, sendChan, ok := subr.channels()
+	subr.mu.RUnlock()
+	if !ok {
+		log.Printf("Channeler is nil, cannot send %s", kind)
+		return
+	}
+
+	select {
+	case sendChan <- [][]byte{reply}:
+		// Message sent successfully
+	case <-time.After(5 * time.Second):
+		log.Println("Warning: Could not send response message - timeout")
+	}
+}
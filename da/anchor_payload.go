@@ -0,0 +1,194 @@
+package da
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/Layer-Edge/bitcoin-da/config"
+	"github.com/Layer-Edge/bitcoin-da/merkle"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Anchor payloads are laid out as
+//
+//	<protocol id> <version> <payload type> <body>
+//
+// with the body depending on the payload type. Chunks of a payload that is
+// too large for one OP_RETURN carry
+//
+//	<group id (4)> <index> <total> <chunk>
+//
+// where the chunks concatenate to <inner payload type> <inner body>.
+const (
+	AnchorPayloadVersion = 0x01
+
+	PayloadTypeRoot       = 0x01 // body is the 32 byte root
+	PayloadTypeChunk      = 0x02 // body is one part of a split payload
+	PayloadTypeRootLeaves = 0x03 // body is the 32 byte root followed by its 32 byte leaves
+
+	// MaxOPReturnDataSize is the default relay policy limit (-datacarriersize)
+	MaxOPReturnDataSize = 80
+
+	chunkGroupSize  = 4
+	chunkHeaderSize = chunkGroupSize + 2
+	maxChunks       = 255
+)
+
+// OP_RETURN policy settings, see `op-return` in the config
+var (
+	opReturnDataLimit      = MaxOPReturnDataSize
+	splitOversizedPayloads = false
+)
+
+// AnchorPayload is a decoded anchor payload
+type AnchorPayload struct {
+	Version byte
+	Type    byte
+	Body    []byte
+
+	// Set for PayloadTypeChunk
+	Group []byte
+	Index int
+	Total int
+}
+
+// InitOPReturnPolicy loads the OP_RETURN size limit and split setting from the config
+func InitOPReturnPolicy(cfg *config.Config) {
+	opReturnDataLimit = cfg.OPReturn.MaxDataSize
+	splitOversizedPayloads = cfg.OPReturn.SplitOversized
+}
+
+// EncodeAnchorPayload serializes a payload of the given type
+func EncodeAnchorPayload(protocolId string, payloadType byte, body []byte) []byte {
+	payload := make([]byte, 0, len(protocolId)+2+len(body))
+	payload = append(payload, protocolId...)
+	payload = append(payload, AnchorPayloadVersion, payloadType)
+	return append(payload, body...)
+}
+
+// RootAnchorPayload encodes a Merkle root given as hex
+func RootAnchorPayload(protocolId string, root string) ([]byte, error) {
+	rootHash, err := merkle.ParseLeaf(root)
+	if err != nil {
+		return nil, fmt.Errorf("invalid root: %w", err)
+	}
+	return EncodeAnchorPayload(protocolId, PayloadTypeRoot, rootHash.Bytes()), nil
+}
+
+// ParseAnchorPayload decodes data if it carries protocolId and a known version
+func ParseAnchorPayload(protocolId string, data []byte) (*AnchorPayload, error) {
+	if !bytes.HasPrefix(data, []byte(protocolId)) {
+		return nil, fmt.Errorf("missing protocol id")
+	}
+	data = data[len(protocolId):]
+	if len(data) < 2 {
+		return nil, fmt.Errorf("payload too short")
+	}
+	if data[0] != AnchorPayloadVersion {
+		return nil, fmt.Errorf("unsupported payload version %d", data[0])
+	}
+
+	p := &AnchorPayload{Version: data[0], Type: data[1], Body: data[2:]}
+	switch p.Type {
+	case PayloadTypeRoot:
+		if len(p.Body) != 32 {
+			return nil, fmt.Errorf("root payload has %d bytes, expected 32", len(p.Body))
+		}
+	case PayloadTypeRootLeaves:
+		if len(p.Body) == 0 || len(p.Body)%32 != 0 {
+			return nil, fmt.Errorf("root and leaves payload has %d bytes, expected a multiple of 32", len(p.Body))
+		}
+	case PayloadTypeChunk:
+		if len(p.Body) <= chunkHeaderSize {
+			return nil, fmt.Errorf("chunk payload too short")
+		}
+		p.Group = p.Body[:chunkGroupSize]
+		p.Index = int(p.Body[chunkGroupSize])
+		p.Total = int(p.Body[chunkGroupSize+1])
+		p.Body = p.Body[chunkHeaderSize:]
+		if p.Index >= p.Total {
+			return nil, fmt.Errorf("chunk index %d out of range for %d chunks", p.Index, p.Total)
+		}
+	default:
+		return nil, fmt.Errorf("unknown payload type %d", p.Type)
+	}
+	return p, nil
+}
+
+// Root returns the anchored root as 0x-prefixed hex, or "" for chunks
+func (p *AnchorPayload) Root() string {
+	switch p.Type {
+	case PayloadTypeRoot, PayloadTypeRootLeaves:
+		return "0x" + hex.EncodeToString(p.Body[:32])
+	default:
+		return ""
+	}
+}
+
+// ChunkPrefix returns the bytes shared by every chunk of a group
+func ChunkPrefix(protocolId string, group []byte) []byte {
+	return EncodeAnchorPayload(protocolId, PayloadTypeChunk, group)
+}
+
+// JoinAnchorChunks reassembles chunks of one group, in any order, into the
+// original payload
+func JoinAnchorChunks(protocolId string, chunks []*AnchorPayload) (*AnchorPayload, error) {
+	if len(chunks) == 0 {
+		return nil, fmt.Errorf("no chunks")
+	}
+
+	total := chunks[0].Total
+	parts := make([][]byte, total)
+	for _, c := range chunks {
+		if c.Type != PayloadTypeChunk || c.Total != total || !bytes.Equal(c.Group, chunks[0].Group) {
+			return nil, fmt.Errorf("chunks belong to different payloads")
+		}
+		parts[c.Index] = c.Body
+	}
+	for i, part := range parts {
+		if part == nil {
+			return nil, fmt.Errorf("missing chunk %d of %d", i, total)
+		}
+	}
+
+	inner := bytes.Join(parts, nil)
+	if !bytes.Equal(crypto.Keccak256(inner)[:chunkGroupSize], chunks[0].Group) {
+		return nil, fmt.Errorf("reassembled payload does not match group id %x", chunks[0].Group)
+	}
+
+	joined := append([]byte(protocolId), AnchorPayloadVersion)
+	return ParseAnchorPayload(protocolId, append(joined, inner...))
+}
+
+// SplitAnchorPayload returns payload unchanged if it fits within limit,
+// otherwise it splits it into chunk payloads that each fit. The group id is
+// the keccak256 prefix of the reassembled payload.
+func SplitAnchorPayload(protocolId string, payload []byte, limit int) ([][]byte, error) {
+	if len(payload) <= limit {
+		return [][]byte{payload}, nil
+	}
+
+	overhead := len(protocolId) + 2 + chunkHeaderSize
+	if overhead >= limit {
+		return nil, fmt.Errorf("protocol id of %d bytes leaves no room for anchor data within %d bytes", len(protocolId), limit)
+	}
+
+	// Drop the protocol id and version, chunks carry the type and body
+	inner := payload[len(protocolId)+1:]
+	chunkSize := limit - overhead
+	total := (len(inner) + chunkSize - 1) / chunkSize
+	if total > maxChunks {
+		return nil, fmt.Errorf("anchor payload of %d bytes needs %d chunks, at most %d are allowed", len(payload), total, maxChunks)
+	}
+
+	group := crypto.Keccak256(inner)[:chunkGroupSize]
+	chunks := make([][]byte, 0, total)
+	for i := 0; i < total; i++ {
+		end := min((i+1)*chunkSize, len(inner))
+		body := append(append([]byte{}, group...), byte(i), byte(total))
+		body = append(body, inner[i*chunkSize:end]...)
+		chunks = append(chunks, EncodeAnchorPayload(protocolId, PayloadTypeChunk, body))
+	}
+	return chunks, nil
+}
@@ -0,0 +1,210 @@
+package da
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/Layer-Edge/bitcoin-da/merkle"
+)
+
+const testProtocolId = "LayerEdge"
+
+// rootLeavesPayload encodes a root followed by n leaves derived from seed
+func rootLeavesPayload(seed string, n int) []byte {
+	body := merkle.HashLeaf([]byte(seed)).Bytes()
+	for i := 0; i < n; i++ {
+		body = append(body, merkle.HashLeaf([]byte(fmt.Sprintf("%s-%d", seed, i))).Bytes()...)
+	}
+	return EncodeAnchorPayload(testProtocolId, PayloadTypeRootLeaves, body)
+}
+
+// parseChunks parses every chunk payload
+func parseChunks(t *testing.T, chunks [][]byte) []*AnchorPayload {
+	t.Helper()
+
+	parsed := make([]*AnchorPayload, len(chunks))
+	for i, chunk := range chunks {
+		p, err := ParseAnchorPayload(testProtocolId, chunk)
+		if err != nil {
+			t.Fatalf("chunk %d: %v", i, err)
+		}
+		parsed[i] = p
+	}
+	return parsed
+}
+
+func TestRootAnchorPayloadRoundTrip(t *testing.T) {
+	root := merkle.HashLeaf([]byte("root")).Hex()
+
+	payload, err := RootAnchorPayload(testProtocolId, root)
+	if err != nil {
+		t.Fatalf("RootAnchorPayload: %v", err)
+	}
+	if len(payload) != len(testProtocolId)+2+32 {
+		t.Errorf("payload has %d bytes", len(payload))
+	}
+
+	p, err := ParseAnchorPayload(testProtocolId, payload)
+	if err != nil {
+		t.Fatalf("ParseAnchorPayload: %v", err)
+	}
+	if p.Version != AnchorPayloadVersion || p.Type != PayloadTypeRoot || p.Root() != root {
+		t.Errorf("parsed version %d, type %d, root %s", p.Version, p.Type, p.Root())
+	}
+
+	if _, err := RootAnchorPayload(testProtocolId, "0x1234"); err == nil {
+		t.Error("encoded a short root")
+	}
+}
+
+func TestParseAnchorPayloadErrors(t *testing.T) {
+	root := merkle.HashLeaf([]byte("root")).Bytes()
+	withVersion := func(version byte, rest ...byte) []byte {
+		return append(append([]byte(testProtocolId), version), rest...)
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "other protocol", data: EncodeAnchorPayload("Other", PayloadTypeRoot, root)},
+		{name: "unversioned hex payload", data: append([]byte(testProtocolId), []byte(fmt.Sprintf("%x", root))...)},
+		{name: "wrong version", data: withVersion(AnchorPayloadVersion+1, append([]byte{PayloadTypeRoot}, root...)...)},
+		{name: "too short", data: withVersion(AnchorPayloadVersion)},
+		{name: "unknown type", data: EncodeAnchorPayload(testProtocolId, 0x7f, root)},
+		{name: "short root", data: EncodeAnchorPayload(testProtocolId, PayloadTypeRoot, root[:31])},
+		{name: "partial leaf", data: EncodeAnchorPayload(testProtocolId, PayloadTypeRootLeaves, append(root, 0x01))},
+		{name: "empty root and leaves", data: EncodeAnchorPayload(testProtocolId, PayloadTypeRootLeaves, nil)},
+		{name: "chunk without data", data: EncodeAnchorPayload(testProtocolId, PayloadTypeChunk, []byte{1, 2, 3, 4, 0, 1})},
+		{name: "chunk index out of range", data: EncodeAnchorPayload(testProtocolId, PayloadTypeChunk, []byte{1, 2, 3, 4, 2, 2, 0xff})},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if p, err := ParseAnchorPayload(testProtocolId, tt.data); err == nil {
+				t.Fatalf("parsed %+v", p)
+			}
+		})
+	}
+}
+
+func TestSplitAndJoinAnchorPayload(t *testing.T) {
+	tests := []struct {
+		name   string
+		leaves int
+		limit  int
+		chunks int
+	}{
+		{name: "root and leaf fit", leaves: 1, limit: 80, chunks: 1},
+		{name: "two chunks", leaves: 2, limit: 80, chunks: 2},
+		{name: "many chunks", leaves: 20, limit: 80, chunks: 11},
+		{name: "large limit", leaves: 20, limit: 400, chunks: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload := rootLeavesPayload("root", tt.leaves)
+			chunks, err := SplitAnchorPayload(testProtocolId, payload, tt.limit)
+			if err != nil {
+				t.Fatalf("SplitAnchorPayload: %v", err)
+			}
+			if len(chunks) != tt.chunks {
+				t.Fatalf("split into %d chunks, want %d", len(chunks), tt.chunks)
+			}
+			if len(chunks) == 1 {
+				if !bytes.Equal(chunks[0], payload) {
+					t.Error("payload that fits was changed")
+				}
+				return
+			}
+			for i, chunk := range chunks {
+				if len(chunk) > tt.limit {
+					t.Errorf("chunk %d has %d bytes, above the %d limit", i, len(chunk), tt.limit)
+				}
+			}
+
+			parsed := parseChunks(t, chunks)
+			reversed := make([]*AnchorPayload, len(parsed))
+			for i, p := range parsed {
+				reversed[len(parsed)-1-i] = p
+			}
+			rotated := append(append([]*AnchorPayload{}, parsed[1:]...), parsed[0])
+
+			for name, order := range map[string][]*AnchorPayload{"in order": parsed, "reversed": reversed, "rotated": rotated} {
+				joined, err := JoinAnchorChunks(testProtocolId, order)
+				if err != nil {
+					t.Fatalf("%s: JoinAnchorChunks: %v", name, err)
+				}
+				if joined.Type != PayloadTypeRootLeaves {
+					t.Errorf("%s: joined type %d", name, joined.Type)
+				}
+				if got := EncodeAnchorPayload(testProtocolId, joined.Type, joined.Body); !bytes.Equal(got, payload) {
+					t.Errorf("%s: joined payload differs from the original", name)
+				}
+			}
+		})
+	}
+}
+
+func TestSplitAnchorPayloadErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload []byte
+		limit   int
+	}{
+		{name: "no room after the chunk header", payload: rootLeavesPayload("root", 1), limit: len(testProtocolId) + 2 + chunkHeaderSize},
+		{name: "too many chunks", payload: rootLeavesPayload("root", 600), limit: 80},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if chunks, err := SplitAnchorPayload(testProtocolId, tt.payload, tt.limit); err == nil {
+				t.Fatalf("split into %d chunks", len(chunks))
+			}
+		})
+	}
+}
+
+func TestJoinAnchorChunksErrors(t *testing.T) {
+	split := func(seed string, leaves int) []*AnchorPayload {
+		chunks, err := SplitAnchorPayload(testProtocolId, rootLeavesPayload(seed, leaves), 80)
+		if err != nil {
+			t.Fatalf("SplitAnchorPayload: %v", err)
+		}
+		return parseChunks(t, chunks)
+	}
+	three := split("root", 4)
+	otherThree := split("other", 4)
+	two := split("root", 2)
+	if len(three) != 3 || len(otherThree) != 3 || len(two) != 2 {
+		t.Fatalf("fixtures split into %d, %d and %d chunks", len(three), len(otherThree), len(two))
+	}
+
+	tampered := *three[1]
+	tampered.Body = append([]byte{}, three[1].Body...)
+	tampered.Body[0] ^= 0xff
+
+	root, err := RootAnchorPayload(testProtocolId, merkle.HashLeaf([]byte("root")).Hex())
+	if err != nil {
+		t.Fatalf("RootAnchorPayload: %v", err)
+	}
+	notChunk := parseChunks(t, [][]byte{root})[0]
+
+	tests := []struct {
+		name   string
+		chunks []*AnchorPayload
+	}{
+		{name: "no chunks"},
+		{name: "missing chunk", chunks: []*AnchorPayload{three[0], three[2]}},
+		{name: "repeated chunk in place of a missing one", chunks: []*AnchorPayload{three[0], three[0], three[2]}},
+		{name: "chunk of another group", chunks: []*AnchorPayload{three[0], otherThree[1], three[2]}},
+		{name: "chunks of a different total", chunks: []*AnchorPayload{three[0], two[1], three[2]}},
+		{name: "tampered chunk", chunks: []*AnchorPayload{three[0], &tampered, three[2]}},
+		{name: "not a chunk", chunks: []*AnchorPayload{three[0], notChunk, three[2]}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if p, err := JoinAnchorChunks(testProtocolId, tt.chunks); err == nil {
+				t.Fatalf("joined %+v", p)
+			}
+		})
+	}
+}
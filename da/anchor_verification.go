@@ -0,0 +1,87 @@
+package da
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/Layer-Edge/bitcoin-da/models"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// AnchorVerification is the outcome of VerifyAnchor
+type AnchorVerification struct {
+	Valid            bool
+	Reason           string
+	BTCTxHash        string
+	BTCBlockNumber   *int64
+	BTCConfirmations int64
+}
+
+// VerifyAnchor checks both levels of the inclusion proof and that its super
+// proof root is anchored on Bitcoin. Anchors seen by the reader service are
+// used first; otherwise the transaction the proof names is fetched from the
+// node and its OP_RETURN outputs are decoded.
+func VerifyAnchor(protocolId string, p *AnchoredInclusionProof) (*AnchorVerification, error) {
+	if p.SuperProof == nil {
+		return &AnchorVerification{Reason: fmt.Sprintf("aggregate %s is not part of a super proof yet", p.Aggregate.Root)}, nil
+	}
+	if err := p.Verify(p.SuperProof.Root); err != nil {
+		return &AnchorVerification{Reason: err.Error()}, nil
+	}
+
+	root := "0x" + normalizeRoot(p.SuperProof.Root)
+	for _, candidate := range []string{root, p.SuperProof.Root} {
+		anchors, err := models.GetBTCAnchorsByRoot(candidate)
+		if err != nil {
+			return nil, err
+		}
+		if len(anchors) > 0 {
+			anchor := anchors[0]
+			return &AnchorVerification{
+				Valid:            true,
+				BTCTxHash:        anchor.TxHash,
+				BTCBlockNumber:   &anchor.BlockHeight,
+				BTCConfirmations: anchor.Confirmations,
+			}, nil
+		}
+	}
+
+	if p.BTCTxHash == nil || *p.BTCTxHash == "" {
+		return &AnchorVerification{Reason: fmt.Sprintf("super proof root %s has no Bitcoin anchor yet", root)}, nil
+	}
+
+	tx, err := GetWalletTransaction(*p.BTCTxHash)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching anchor transaction %s: %w", *p.BTCTxHash, err)
+	}
+	raw, err := hex.DecodeString(tx.Hex)
+	if err != nil {
+		return nil, fmt.Errorf("invalid anchor transaction hex: %w", err)
+	}
+	var msgTx wire.MsgTx
+	if err := msgTx.Deserialize(bytes.NewReader(raw)); err != nil {
+		return nil, fmt.Errorf("error decoding anchor transaction: %w", err)
+	}
+
+	for _, out := range msgTx.TxOut {
+		data, ok := opReturnData(out.PkScript)
+		if !ok {
+			continue
+		}
+		anchored, _, ok := DecodeAnchorPayload(protocolId, data)
+		if ok && normalizeRoot(anchored) == normalizeRoot(root) {
+			return &AnchorVerification{
+				Valid:            true,
+				BTCTxHash:        tx.Txid,
+				BTCBlockNumber:   tx.Blockheight,
+				BTCConfirmations: tx.Confirmations,
+			}, nil
+		}
+	}
+
+	return &AnchorVerification{
+		BTCTxHash: tx.Txid,
+		Reason:    fmt.Sprintf("transaction %s does not anchor super proof root %s", tx.Txid, root),
+	}, nil
+}
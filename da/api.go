@@ -0,0 +1,400 @@
+package da
+
+import (
+	"context"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Layer-Edge/bitcoin-da/config"
+	"github.com/Layer-Edge/bitcoin-da/models"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// SubmitProofRequest is the body of POST /v1/proofs. Proof is hex encoded,
+// with or without 0x, and carries the same bytes as the second frame of a
+// data socket message. Namespace defaults to the one derived from Label.
+// Signature is the hex signature frame, required with submitter-auth.
+// ProofType selects the proof-validation validator.
+type SubmitProofRequest struct {
+	Namespace string `json:"namespace,omitempty"`
+	Label     string `json:"label"`
+	Proof     string `json:"proof"`
+	Signature string `json:"signature,omitempty"`
+	ProofType string `json:"proof_type,omitempty"`
+}
+
+// AggregateView is the API representation of a row in aggregated_proofs
+type AggregateView struct {
+	ID               string           `json:"id"`
+	Root             string           `json:"root"`
+	Leaves           []string         `json:"leaves"`
+	Proofs           []string         `json:"proofs,omitempty"`     // hex ABI proofs, regular aggregates only
+	Namespaces       map[string][]int `json:"namespaces,omitempty"` // leaf indexes per namespace, regular aggregates only
+	SuperProof       bool             `json:"super_proof"`
+	Backend          string           `json:"backend"`                 // merkle | snark
+	SnarkProof       string           `json:"snark_proof,omitempty"`   // hex aggregated proof, snark aggregates only
+	PublicInputs     string           `json:"public_inputs,omitempty"` // hex public witness of snark_proof
+	BlockHeight      int64            `json:"block_height"`
+	TransactionHash  string           `json:"transaction_hash"`
+	Success          bool             `json:"success"`
+	Timestamp        time.Time        `json:"timestamp"`
+	BTCTxHash        *string          `json:"btc_tx_hash,omitempty"`
+	BTCBlockNumber   *int64           `json:"btc_block_number,omitempty"`
+	BTCBlockHash     *string          `json:"btc_block_hash,omitempty"`
+	BTCConfirmations int64            `json:"btc_confirmations"`
+	BTCConfirmedAt   *time.Time       `json:"btc_confirmed_at,omitempty"`
+}
+
+// SuperProofPage is the body of GET /v1/superproofs
+type SuperProofPage struct {
+	Limit       int             `json:"limit"`
+	Offset      int             `json:"offset"`
+	SuperProofs []AggregateView `json:"super_proofs"`
+}
+
+// NamespaceProofView is one proof in GET /v1/namespaces/{namespace}/proofs
+type NamespaceProofView struct {
+	ProofID       string     `json:"proof_id"`
+	Label         string     `json:"label"`
+	ReceivedAt    time.Time  `json:"received_at"`
+	ConsumedAt    *time.Time `json:"consumed_at,omitempty"`
+	AggregateRoot *string    `json:"aggregate_root,omitempty"`
+	LeafIndex     *int       `json:"leaf_index,omitempty"`
+}
+
+// NamespacePage is the body of the GET /v1/namespaces/{namespace} listings
+type NamespacePage struct {
+	Namespace  string               `json:"namespace"`
+	Limit      int                  `json:"limit"`
+	Offset     int                  `json:"offset"`
+	Proofs     []NamespaceProofView `json:"proofs,omitempty"`
+	Aggregates []AggregateView      `json:"aggregates,omitempty"`
+}
+
+type apiError struct {
+	Error string `json:"error"`
+}
+
+type apiServer struct {
+	submitTimeout time.Duration
+	maxBodyBytes  int64
+
+	// Lookups, replaceable so the server can run against fixtures
+	proofStatus func(leaf string) (*ProofStatus, error)
+}
+
+// NewAggregateView converts a stored aggregated proof for the API
+func NewAggregateView(ap *models.AggregatedProof) (*AggregateView, error) {
+	leaves, err := AggregateLeaves(ap)
+	if err != nil {
+		return nil, err
+	}
+
+	view := &AggregateView{
+		ID:               ap.ID,
+		Root:             ap.MerkleRoot,
+		Leaves:           leaves,
+		SuperProof:       ap.IsSuperProof(),
+		Backend:          ap.Backend,
+		BlockHeight:      ap.BlockHeight,
+		TransactionHash:  ap.TransactionHash,
+		Success:          ap.Success,
+		Timestamp:        ap.Timestamp,
+		BTCTxHash:        ap.BTCTxHash,
+		BTCBlockNumber:   ap.BTCBlockNumber,
+		BTCBlockHash:     ap.BTCBlockHash,
+		BTCConfirmations: ap.BTCConfirmations,
+		BTCConfirmedAt:   ap.BTCConfirmedAt,
+	}
+	if ap.Backend == AggregationBackendSnark {
+		view.SnarkProof = "0x" + hex.EncodeToString(ap.AggregateProof)
+		view.PublicInputs = "0x" + hex.EncodeToString(ap.PublicInputs)
+	}
+	if !view.SuperProof {
+		view.Proofs = ap.Proofs
+		view.Namespaces, err = NamespaceLeafIndexes(view.Root)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return view, nil
+}
+
+// APIServer serves the HTTP/JSON submission and query API. Submissions go
+// through the same pipeline as the data socket, so HashBlockSubscriber must
+// be running in the same process; queries use the database connection it
+// opens.
+func APIServer(cfg *config.Config) {
+	s := &apiServer{
+		submitTimeout: time.Duration(cfg.API.SubmitTimeoutSeconds) * time.Second,
+		maxBodyBytes:  cfg.API.MaxBodyBytes,
+		proofStatus:   GetProofStatus,
+	}
+
+	server := &http.Server{
+		Addr:              cfg.API.ListenAddress,
+		Handler:           s.routes(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	log.Printf("Starting API server on %s", cfg.API.ListenAddress)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatalf("API server stopped: %v", err)
+	}
+}
+
+func (s *apiServer) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/proofs", s.handleSubmitProof)
+	mux.HandleFunc("GET /v1/proofs/{leaf}", s.handleGetProof)
+	mux.HandleFunc("GET /v1/aggregates/{root}", s.handleGetAggregate)
+	mux.HandleFunc("GET /v1/superproofs", s.handleListSuperProofs)
+	mux.HandleFunc("GET /v1/namespaces/{namespace}/proofs", s.handleListNamespaceProofs)
+	mux.HandleFunc("GET /v1/namespaces/{namespace}/aggregates", s.handleListNamespaceAggregates)
+	return mux
+}
+
+func (s *apiServer) handleSubmitProof(w http.ResponseWriter, r *http.Request) {
+	var req SubmitProofRequest
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, s.maxBodyBytes))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		writeAck(w, NewAck(AckStatusRejected, "", fmt.Sprintf("invalid request body: %v", err)))
+		return
+	}
+
+	proof, err := hex.DecodeString(strings.TrimPrefix(req.Proof, "0x"))
+	if err != nil {
+		writeAck(w, NewAck(AckStatusRejected, "", fmt.Sprintf("proof is not valid hex: %v", err)))
+		return
+	}
+
+	signature, err := hex.DecodeString(strings.TrimPrefix(req.Signature, "0x"))
+	if err != nil {
+		writeAck(w, NewAck(AckStatusRejected, "", fmt.Sprintf("signature is not valid hex: %v", err)))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), s.submitTimeout)
+	defer cancel()
+
+	ack, err := SubmitProof(ctx, &Submission{
+		Namespace: req.Namespace,
+		Label:     req.Label,
+		ProofType: req.ProofType,
+		Proof:     proof,
+		Signature: signature,
+	})
+	if err != nil {
+		log.Printf("API submission failed: %v", err)
+		ack = SubmissionErrorAck(proof, err)
+	}
+	writeAck(w, ack)
+}
+
+func (s *apiServer) handleGetProof(w http.ResponseWriter, r *http.Request) {
+	leaf := r.PathValue("leaf")
+	status, err := s.proofStatus(leaf)
+	if errors.Is(err, ErrInvalidProofID) {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to fetch status of proof %s: %v", leaf, err)
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("failed to fetch proof status"))
+		return
+	}
+	if status.Stage == ProofStageUnknown {
+		writeJSON(w, http.StatusNotFound, status)
+		return
+	}
+	writeJSON(w, http.StatusOK, status)
+}
+
+func (s *apiServer) handleGetAggregate(w http.ResponseWriter, r *http.Request) {
+	root := r.PathValue("root")
+	aggregate, err := getAggregateByRoot(root)
+	if errors.Is(err, sql.ErrNoRows) {
+		writeError(w, http.StatusNotFound, fmt.Errorf("no aggregate with root %s", root))
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to fetch aggregate %s: %v", root, err)
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("failed to fetch aggregate"))
+		return
+	}
+
+	view, err := NewAggregateView(aggregate)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, view)
+}
+
+func (s *apiServer) handleListSuperProofs(w http.ResponseWriter, r *http.Request) {
+	limit, offset, err := queryPage(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	proofs, err := models.GetSuperProofs(limit, offset)
+	if err != nil {
+		log.Printf("Failed to fetch super proofs: %v", err)
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("failed to fetch super proofs"))
+		return
+	}
+
+	page := SuperProofPage{Limit: limit, Offset: offset, SuperProofs: make([]AggregateView, 0, len(proofs))}
+	for i := range proofs {
+		view, err := NewAggregateView(&proofs[i])
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		page.SuperProofs = append(page.SuperProofs, *view)
+	}
+	writeJSON(w, http.StatusOK, page)
+}
+
+func (s *apiServer) handleListNamespaceProofs(w http.ResponseWriter, r *http.Request) {
+	namespace := r.PathValue("namespace")
+	limit, offset, err := queryPage(r)
+	if err == nil {
+		err = ValidateNamespace(namespace)
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	proofs, err := models.GetPendingProofsByNamespace(namespace, limit, offset)
+	if err != nil {
+		log.Printf("Failed to fetch proofs of namespace %s: %v", namespace, err)
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("failed to fetch proofs"))
+		return
+	}
+
+	page := NamespacePage{Namespace: namespace, Limit: limit, Offset: offset, Proofs: make([]NamespaceProofView, 0, len(proofs))}
+	for _, proof := range proofs {
+		page.Proofs = append(page.Proofs, NamespaceProofView{
+			ProofID:       proof.LeafHash,
+			Label:         proof.Label,
+			ReceivedAt:    proof.ReceivedAt,
+			ConsumedAt:    proof.ConsumedAt,
+			AggregateRoot: proof.AggregateRoot,
+			LeafIndex:     proof.LeafIndex,
+		})
+	}
+	writeJSON(w, http.StatusOK, page)
+}
+
+func (s *apiServer) handleListNamespaceAggregates(w http.ResponseWriter, r *http.Request) {
+	namespace := r.PathValue("namespace")
+	limit, offset, err := queryPage(r)
+	if err == nil {
+		err = ValidateNamespace(namespace)
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	aggregates, err := models.GetAggregatedProofsByNamespace(namespace, limit, offset)
+	if err != nil {
+		log.Printf("Failed to fetch aggregates of namespace %s: %v", namespace, err)
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("failed to fetch aggregates"))
+		return
+	}
+
+	page := NamespacePage{Namespace: namespace, Limit: limit, Offset: offset, Aggregates: make([]AggregateView, 0, len(aggregates))}
+	for i := range aggregates {
+		view, err := NewAggregateView(&aggregates[i])
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		page.Aggregates = append(page.Aggregates, *view)
+	}
+	writeJSON(w, http.StatusOK, page)
+}
+
+// getAggregateByRoot looks up root as given and, failing that, with its 0x
+// prefix toggled, since roots are stored as returned by the merkle backend
+func getAggregateByRoot(root string) (*models.AggregatedProof, error) {
+	aggregate, err := models.GetAggregatedProofByRoot(root)
+	if !errors.Is(err, sql.ErrNoRows) {
+		return aggregate, err
+	}
+
+	alt := "0x" + root
+	if strings.HasPrefix(root, "0x") {
+		alt = strings.TrimPrefix(root, "0x")
+	}
+	return models.GetAggregatedProofByRoot(alt)
+}
+
+// queryPage reads the limit and offset query parameters
+func queryPage(r *http.Request) (int, int, error) {
+	limit, err := queryInt(r, "limit", defaultPageSize)
+	if err != nil || limit <= 0 || limit > maxPageSize {
+		return 0, 0, fmt.Errorf("limit must be between 1 and %d", maxPageSize)
+	}
+	offset, err := queryInt(r, "offset", 0)
+	if err != nil || offset < 0 {
+		return 0, 0, fmt.Errorf("offset must not be negative")
+	}
+	return limit, offset, nil
+}
+
+func queryInt(r *http.Request, key string, def int) (int, error) {
+	value := r.URL.Query().Get(key)
+	if value == "" {
+		return def, nil
+	}
+	return strconv.Atoi(value)
+}
+
+// writeAck maps the ACK status onto an HTTP status code
+func writeAck(w http.ResponseWriter, ack *Ack) {
+	code := http.StatusAccepted
+	switch ack.Status {
+	case AckStatusDuplicate:
+		code = http.StatusOK
+	case AckStatusRejected:
+		code = http.StatusBadRequest
+	case AckStatusFailed:
+		code = http.StatusServiceUnavailable
+	case AckStatusUnknown:
+		code = http.StatusGatewayTimeout
+	case AckStatusThrottled:
+		code = http.StatusTooManyRequests
+		w.Header().Set("Retry-After", strconv.Itoa(ack.RetryAfterSeconds))
+	}
+	writeJSON(w, code, ack)
+}
+
+func writeError(w http.ResponseWriter, code int, err error) {
+	writeJSON(w, code, apiError{Error: err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("Failed to write API response: %v", err)
+	}
+}
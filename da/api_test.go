@@ -0,0 +1,80 @@
+package da
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Layer-Edge/bitcoin-da/merkle"
+)
+
+// fixtureProofStatus answers status lookups for the leaves of the API and
+// gRPC tests
+func fixtureProofStatus(leaf string) (*ProofStatus, error) {
+	stored := merkle.HashLeaf([]byte("stored")).Hex()
+	switch leaf {
+	case stored:
+		return &ProofStatus{Version: AckVersion, ProofID: stored, Stage: ProofStageStored}, nil
+	case merkle.HashLeaf([]byte("db down")).Hex():
+		return nil, errors.New("failed to get database connection")
+	}
+	if _, err := merkle.ParseLeaf(leaf); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidProofID, err)
+	}
+	return &ProofStatus{Version: AckVersion, ProofID: leaf, Stage: ProofStageUnknown}, nil
+}
+
+func TestHandleGetProof(t *testing.T) {
+	s := &apiServer{proofStatus: fixtureProofStatus}
+	server := httptest.NewServer(s.routes())
+	t.Cleanup(server.Close)
+
+	tests := []struct {
+		name  string
+		leaf  string
+		code  int
+		stage string
+	}{
+		{name: "stored proof", leaf: merkle.HashLeaf([]byte("stored")).Hex(), code: http.StatusOK, stage: ProofStageStored},
+		{name: "unknown proof", leaf: merkle.HashLeaf([]byte("unknown")).Hex(), code: http.StatusNotFound, stage: ProofStageUnknown},
+		{name: "invalid leaf", leaf: "0x1234", code: http.StatusBadRequest},
+		{name: "lookup fails", leaf: merkle.HashLeaf([]byte("db down")).Hex(), code: http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := http.Get(server.URL + "/v1/proofs/" + tt.leaf)
+			if err != nil {
+				t.Fatalf("GET: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.code {
+				t.Fatalf("status code = %d, want %d", resp.StatusCode, tt.code)
+			}
+			var body struct {
+				Stage string `json:"stage"`
+				Error string `json:"error"`
+			}
+			if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if body.Stage != tt.stage {
+				t.Errorf("stage = %q, want %q", body.Stage, tt.stage)
+			}
+			if tt.code == http.StatusInternalServerError && body.Error != "failed to fetch proof status" {
+				t.Errorf("error = %q, lookup errors must not reach the client", body.Error)
+			}
+		})
+	}
+}
+
+func TestGetProofStatusInvalidLeaf(t *testing.T) {
+	for _, leaf := range []string{"", "0x1234", "not a hash"} {
+		if _, err := GetProofStatus(leaf); !errors.Is(err, ErrInvalidProofID) {
+			t.Errorf("GetProofStatus(%q) = %v, want ErrInvalidProofID", leaf, err)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package da
+
+import (
+	"crypto/rand"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Layer-Edge/bitcoin-da/models"
+)
+
+// BatchState is the stage of a batch's lifecycle
+type BatchState int
+
+const (
+	BatchOpen      BatchState = iota // accepting proofs
+	BatchSealed                      // closed to new proofs, waiting to be stored on LayerEdge
+	BatchPublished                   // every proof is stored
+)
+
+func (s BatchState) String() string {
+	switch s {
+	case BatchOpen:
+		return "open"
+	case BatchSealed:
+		return "sealed"
+	case BatchPublished:
+		return "published"
+	}
+	return fmt.Sprintf("BatchState(%d)", s)
+}
+
+// Batch is a window of proofs aggregated together. The seal stage appends
+// proofs to the open batch and seals it; the publish stage then stores it on
+// LayerEdge, removing proofs as their transactions succeed, until it is
+// published. A batch that fails to publish keeps its unstored proofs and is
+// retried.
+type Batch struct {
+	ID    string
+	Start time.Time // aligned boundary the batch opened at
+
+	mu       sync.Mutex
+	state    BatchState
+	proofs   []*models.PendingProof // proofs not stored yet, in leaf order
+	leaves   map[string]bool
+	attempts int
+}
+
+// NewBatch opens an empty batch at start
+func NewBatch(start time.Time) *Batch {
+	return &Batch{ID: newBatchID(start), Start: start, leaves: map[string]bool{}}
+}
+
+// newBatchID identifies a batch across restarts by its opening time and a
+// random suffix
+func newBatchID(start time.Time) string {
+	suffix := make([]byte, 4)
+	rand.Read(suffix)
+	return fmt.Sprintf("%d-%x", start.Unix(), suffix)
+}
+
+// RestoreSealedBatch rebuilds a batch that was sealed but not published
+// before a restart from its unconsumed proofs
+func RestoreSealedBatch(id string, proofs []*models.PendingProof) *Batch {
+	b := &Batch{ID: id, state: BatchSealed, leaves: map[string]bool{}}
+	if len(proofs) > 0 {
+		b.Start = proofs[0].ReceivedAt
+	}
+	for _, pending := range proofs {
+		if !b.leaves[pending.LeafHash] {
+			b.proofs = append(b.proofs, pending)
+			b.leaves[pending.LeafHash] = true
+		}
+	}
+	return b
+}
+
+// Append adds a proof to an open batch. It reports false when the leaf is
+// already in the batch.
+func (b *Batch) Append(pending *models.PendingProof) (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state != BatchOpen {
+		return false, fmt.Errorf("batch %s is %s", b.ID, b.state)
+	}
+	if b.leaves[pending.LeafHash] {
+		return false, nil
+	}
+	b.proofs = append(b.proofs, pending)
+	b.leaves[pending.LeafHash] = true
+	return true, nil
+}
+
+// Seal closes an open batch to new proofs. Empty batches cannot be sealed.
+func (b *Batch) Seal() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state != BatchOpen {
+		return fmt.Errorf("batch %s is %s", b.ID, b.state)
+	}
+	if len(b.proofs) == 0 {
+		return fmt.Errorf("batch %s is empty", b.ID)
+	}
+	b.state = BatchSealed
+	return nil
+}
+
+// MarkStored removes the first n proofs of a sealed batch once they are
+// stored on LayerEdge. The batch is published when none are left.
+func (b *Batch) MarkStored(n int) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state != BatchSealed {
+		return fmt.Errorf("batch %s is %s", b.ID, b.state)
+	}
+	if n > len(b.proofs) {
+		return fmt.Errorf("batch %s has %d proofs left, not %d", b.ID, len(b.proofs), n)
+	}
+	for _, pending := range b.proofs[:n] {
+		delete(b.leaves, pending.LeafHash)
+	}
+	b.proofs = b.proofs[n:]
+	if len(b.proofs) == 0 {
+		b.state = BatchPublished
+	}
+	return nil
+}
+
+// Attempt counts a publish attempt and returns how many were made
+func (b *Batch) Attempt() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.attempts++
+	return b.attempts
+}
+
+func (b *Batch) State() BatchState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+// Len is the number of proofs not stored yet
+func (b *Batch) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.proofs)
+}
+
+// Proofs returns the proofs not stored yet, in leaf order
+func (b *Batch) Proofs() []*models.PendingProof {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]*models.PendingProof(nil), b.proofs...)
+}
+
+// IDs returns the pending proof ids of the proofs not stored yet
+func (b *Batch) IDs() []int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ids := make([]int64, len(b.proofs))
+	for i, pending := range b.proofs {
+		ids[i] = pending.ID
+	}
+	return ids
+}
+
+// Stats describes the proofs not stored yet for the sealing policy
+func (b *Batch) Stats() BatchStats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	stats := BatchStats{ID: b.ID, Start: b.Start}
+	for _, pending := range b.proofs {
+		stats.Leaves = append(stats.Leaves, pending.LeafHash)
+		stats.Sizes = append(stats.Sizes, len(pending.Proof))
+	}
+	return stats
+}
@@ -0,0 +1,278 @@
+package da
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Layer-Edge/bitcoin-da/models"
+)
+
+// batchStep is one operation on a batch and what it should leave behind
+type batchStep struct {
+	op   string // append | seal | stored
+	id   int64  // append: pending proof id
+	leaf string // append: leaf hash
+	n    int    // stored: number of proofs stored
+
+	added bool // append: the proof was added
+	err   bool // the operation fails
+
+	state BatchState
+	ids   []int64 // proofs left in the batch
+}
+
+func appendStep(id int64, leaf string, added bool, err bool, state BatchState, ids ...int64) batchStep {
+	return batchStep{op: "append", id: id, leaf: leaf, added: added, err: err, state: state, ids: ids}
+}
+
+func sealStep(err bool, state BatchState, ids ...int64) batchStep {
+	return batchStep{op: "seal", err: err, state: state, ids: ids}
+}
+
+func storedStep(n int, err bool, state BatchState, ids ...int64) batchStep {
+	return batchStep{op: "stored", n: n, err: err, state: state, ids: ids}
+}
+
+func TestBatch(t *testing.T) {
+	tests := []struct {
+		name  string
+		steps []batchStep
+	}{
+		{
+			name: "append and seal",
+			steps: []batchStep{
+				appendStep(1, "a", true, false, BatchOpen, 1),
+				appendStep(2, "b", true, false, BatchOpen, 1, 2),
+				sealStep(false, BatchSealed, 1, 2),
+			},
+		},
+		{
+			name: "duplicate leaf is not added",
+			steps: []batchStep{
+				appendStep(1, "a", true, false, BatchOpen, 1),
+				appendStep(2, "a", false, false, BatchOpen, 1),
+			},
+		},
+		{
+			name: "append to a sealed batch is rejected",
+			steps: []batchStep{
+				appendStep(1, "a", true, false, BatchOpen, 1),
+				sealStep(false, BatchSealed, 1),
+				appendStep(2, "b", false, true, BatchSealed, 1),
+			},
+		},
+		{
+			name: "empty batch cannot be sealed",
+			steps: []batchStep{
+				sealStep(true, BatchOpen),
+			},
+		},
+		{
+			name: "sealed batch cannot be sealed again",
+			steps: []batchStep{
+				appendStep(1, "a", true, false, BatchOpen, 1),
+				sealStep(false, BatchSealed, 1),
+				sealStep(true, BatchSealed, 1),
+			},
+		},
+		{
+			name: "partial store keeps the rest sealed, then publishes",
+			steps: []batchStep{
+				appendStep(1, "a", true, false, BatchOpen, 1),
+				appendStep(2, "b", true, false, BatchOpen, 1, 2),
+				appendStep(3, "c", true, false, BatchOpen, 1, 2, 3),
+				sealStep(false, BatchSealed, 1, 2, 3),
+				storedStep(2, false, BatchSealed, 3),
+				storedStep(1, false, BatchPublished),
+			},
+		},
+		{
+			name: "storing more proofs than are left fails",
+			steps: []batchStep{
+				appendStep(1, "a", true, false, BatchOpen, 1),
+				sealStep(false, BatchSealed, 1),
+				storedStep(2, true, BatchSealed, 1),
+			},
+		},
+		{
+			name: "open batch cannot be stored",
+			steps: []batchStep{
+				appendStep(1, "a", true, false, BatchOpen, 1),
+				storedStep(1, true, BatchOpen, 1),
+			},
+		},
+		{
+			name: "published batch cannot be stored again",
+			steps: []batchStep{
+				appendStep(1, "a", true, false, BatchOpen, 1),
+				sealStep(false, BatchSealed, 1),
+				storedStep(1, false, BatchPublished),
+				storedStep(1, true, BatchPublished),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewBatch(time.Unix(1700000000, 0))
+			for i, step := range tt.steps {
+				var err error
+				switch step.op {
+				case "append":
+					var added bool
+					added, err = b.Append(&models.PendingProof{ID: step.id, LeafHash: step.leaf})
+					if added != step.added {
+						t.Errorf("step %d: added = %v, want %v", i, added, step.added)
+					}
+				case "seal":
+					err = b.Seal()
+				case "stored":
+					err = b.MarkStored(step.n)
+				}
+				if (err != nil) != step.err {
+					t.Errorf("step %d %s: err = %v, want error %v", i, step.op, err, step.err)
+				}
+				if b.State() != step.state {
+					t.Errorf("step %d %s: state = %v, want %v", i, step.op, b.State(), step.state)
+				}
+				if ids := b.IDs(); !reflect.DeepEqual(ids, append([]int64{}, step.ids...)) {
+					t.Errorf("step %d %s: proofs = %v, want %v", i, step.op, ids, step.ids)
+				}
+				if b.Len() != len(step.ids) || len(b.Stats().Leaves) != len(step.ids) {
+					t.Errorf("step %d %s: len = %d, stats leaves = %d, want %d", i, step.op, b.Len(), len(b.Stats().Leaves), len(step.ids))
+				}
+			}
+		})
+	}
+}
+
+func TestNewBatchIDsAreUnique(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	seen := map[string]bool{}
+	for i := 0; i < 100; i++ {
+		id := NewBatch(start).ID
+		if seen[id] {
+			t.Fatalf("batch id %s was generated twice", id)
+		}
+		if len(id) > 32 {
+			t.Fatalf("batch id %s does not fit pending_proofs.batch_id", id)
+		}
+		seen[id] = true
+	}
+}
+
+func TestRestoreSealedBatch(t *testing.T) {
+	received := time.Unix(1700000000, 0)
+	proof := func(id int64, leaf string) *models.PendingProof {
+		return &models.PendingProof{ID: id, LeafHash: leaf, ReceivedAt: received.Add(time.Duration(id) * time.Second)}
+	}
+
+	tests := []struct {
+		name   string
+		proofs []*models.PendingProof
+		want   []int64
+	}{
+		{name: "keeps leaf order", proofs: []*models.PendingProof{proof(1, "a"), proof(2, "b"), proof(3, "c")}, want: []int64{1, 2, 3}},
+		{name: "drops duplicate leaves", proofs: []*models.PendingProof{proof(1, "a"), proof(2, "a"), proof(3, "b"), proof(4, "b")}, want: []int64{1, 3}},
+		{name: "single proof", proofs: []*models.PendingProof{proof(7, "a")}, want: []int64{7}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := RestoreSealedBatch("batch-1", tt.proofs)
+			if b.ID != "batch-1" {
+				t.Errorf("id = %q, want batch-1", b.ID)
+			}
+			if b.State() != BatchSealed {
+				t.Errorf("state = %v, want sealed", b.State())
+			}
+			if !b.Start.Equal(tt.proofs[0].ReceivedAt) {
+				t.Errorf("start = %v, want the first proof's %v", b.Start, tt.proofs[0].ReceivedAt)
+			}
+			if ids := b.IDs(); !reflect.DeepEqual(ids, tt.want) {
+				t.Errorf("proofs = %v, want %v", ids, tt.want)
+			}
+			if _, err := b.Append(proof(99, "z")); err == nil {
+				t.Error("restored batch accepted a new proof")
+			}
+		})
+	}
+}
+
+func TestRestoreBatches(t *testing.T) {
+	batch := func(id string) *string { return &id }
+	row := func(id int64, batchID *string) models.PendingProof {
+		return models.PendingProof{ID: id, LeafHash: fmt.Sprintf("leaf-%d", id), BatchID: batchID}
+	}
+
+	tests := []struct {
+		name   string
+		rows   []models.PendingProof
+		sealed map[string][]int64
+		order  []string
+		open   []int64
+	}{
+		{
+			name: "sealed batches in sealing order, the rest open",
+			rows: []models.PendingProof{
+				row(1, batch("b1")), row(2, batch("b1")),
+				row(3, batch("b2")),
+				row(4, nil), row(5, nil),
+			},
+			sealed: map[string][]int64{"b1": {1, 2}, "b2": {3}},
+			order:  []string{"b1", "b2"},
+			open:   []int64{4, 5},
+		},
+		{
+			// Proofs restored into the open batch before an earlier restart
+			// can be sealed into a batch after newer ones
+			name: "interleaved rows keep the batch of their first proof first",
+			rows: []models.PendingProof{
+				row(1, batch("b2")), row(2, nil), row(3, batch("b1")), row(4, batch("b2")), row(5, batch("b1")),
+			},
+			sealed: map[string][]int64{"b1": {3, 5}, "b2": {1, 4}},
+			order:  []string{"b2", "b1"},
+			open:   []int64{2},
+		},
+		{
+			name:  "only open proofs",
+			rows:  []models.PendingProof{row(1, nil), row(2, nil)},
+			order: []string{},
+			open:  []int64{1, 2},
+		},
+		{
+			name:  "nothing to restore",
+			order: []string{},
+		},
+	}
+
+	p := &writerPipeline{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sealed, open := p.restoreBatches(tt.rows)
+
+			order := []string{}
+			for _, b := range sealed {
+				order = append(order, b.ID)
+				if b.State() != BatchSealed {
+					t.Errorf("batch %s is %v, want sealed", b.ID, b.State())
+				}
+				if ids := b.IDs(); !reflect.DeepEqual(ids, tt.sealed[b.ID]) {
+					t.Errorf("batch %s proofs = %v, want %v", b.ID, ids, tt.sealed[b.ID])
+				}
+			}
+			if !reflect.DeepEqual(order, tt.order) {
+				t.Errorf("sealed batches = %v, want %v", order, tt.order)
+			}
+
+			var openIDs []int64
+			for _, pending := range open {
+				openIDs = append(openIDs, pending.ID)
+			}
+			if !reflect.DeepEqual(openIDs, tt.open) {
+				t.Errorf("open proofs = %v, want %v", openIDs, tt.open)
+			}
+		})
+	}
+}
@@ -0,0 +1,356 @@
+package da
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Layer-Edge/bitcoin-da/config"
+	"github.com/Layer-Edge/bitcoin-da/models"
+	"github.com/Layer-Edge/bitcoin-da/utils"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// Anchor is a protocol-id tagged OP_RETURN output found in a block
+type Anchor struct {
+	TxHash  string
+	Vout    int
+	Payload []byte
+	Root    string
+	Chunk   *AnchorPayload // set for one part of a split payload
+}
+
+// DecodeAnchorPayload strips the protocol id from OP_RETURN data and returns
+// the decoded payload, or nil for legacy anchors that carry the root as hex
+// text. ok is false if data is not one of our anchors.
+func DecodeAnchorPayload(protocolId string, data []byte) (root string, payload *AnchorPayload, ok bool) {
+	if !bytes.HasPrefix(data, []byte(protocolId)) || len(data) == len(protocolId) {
+		return "", nil, false
+	}
+	if payload, err := ParseAnchorPayload(protocolId, data); err == nil {
+		return payload.Root(), payload, true
+	}
+	return string(data[len(protocolId):]), nil, true
+}
+
+// opReturnData returns the concatenated pushes of an OP_RETURN script
+func opReturnData(script []byte) ([]byte, bool) {
+	if len(script) == 0 || script[0] != txscript.OP_RETURN {
+		return nil, false
+	}
+
+	pushes, err := txscript.PushedData(script[1:])
+	if err != nil {
+		return nil, false
+	}
+
+	return bytes.Join(pushes, nil), true
+}
+
+// FindAnchors scans every transaction output of block for our OP_RETURN anchors
+func FindAnchors(block *wire.MsgBlock, protocolId string) []Anchor {
+	anchors := []Anchor{}
+	for _, tx := range block.Transactions {
+		for vout, out := range tx.TxOut {
+			data, ok := opReturnData(out.PkScript)
+			if !ok {
+				continue
+			}
+			root, payload, ok := DecodeAnchorPayload(protocolId, data)
+			if !ok {
+				continue
+			}
+			anchor := Anchor{
+				TxHash:  tx.TxHash().String(),
+				Vout:    vout,
+				Payload: data,
+				Root:    root,
+			}
+			if payload != nil && payload.Type == PayloadTypeChunk {
+				anchor.Chunk = payload
+			}
+			anchors = append(anchors, anchor)
+		}
+	}
+	return anchors
+}
+
+// BTCReader follows the Bitcoin chain, either through bitcoind's ZMQ
+// rawblock/hashblock topics or by polling getblock, and records every anchor
+// tagged with cfg.ProtocolId
+func BTCReader(cfg *config.Config) {
+	err := models.InitDB(cfg.PostgresConnectionURI)
+	if err != nil {
+		log.Fatalf("Error initializing DB Connection: %v", err)
+	}
+	defer func() {
+		if err := models.CloseDB(); err != nil {
+			log.Printf("Error closing database: %v", err)
+		}
+	}()
+
+	InitOPReturnRPC(cfg.BtcEndpoint, cfg.Auth, cfg.WalletPassphrase)
+
+	reader := &blockReader{
+		protocolId: cfg.ProtocolId,
+		next:       cfg.Reader.StartHeight,
+		finalDepth: cfg.ConfirmationTracker.RequiredConfirmations,
+	}
+	if reader.next == 0 {
+		tip, err := GetBlockCount()
+		if err != nil {
+			log.Fatalf("Error getting Bitcoin block count: %v", err)
+		}
+		reader.next = tip
+	}
+
+	log.Printf("Starting Bitcoin reader in %s mode from height %d", cfg.Reader.Mode, reader.next)
+
+	if cfg.Reader.Mode == "poll" {
+		reader.poll(time.Duration(cfg.Reader.PollIntervalSeconds) * time.Second)
+		return
+	}
+	reader.subscribe(cfg)
+}
+
+type blockReader struct {
+	protocolId string
+	next       int64 // next height that has not been scanned yet
+	finalDepth int64 // anchors with fewer confirmations are refreshed
+}
+
+func (r *blockReader) poll(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := r.catchUp(); err != nil {
+			utils.LogProcessingError("BTCReader", "Failed to catch up", err, map[string]interface{}{
+				"height": r.next,
+			})
+		}
+		r.refresh()
+		<-ticker.C
+	}
+}
+
+// catchUp scans every block from r.next up to the current tip. It stops at
+// the first block that fails, which is scanned again on the next call.
+func (r *blockReader) catchUp() error {
+	tip, err := GetBlockCount()
+	if err != nil {
+		return fmt.Errorf("failed to get block count: %w", err)
+	}
+
+	for r.next <= tip {
+		hash, err := GetBlockHash(r.next)
+		if err != nil {
+			return fmt.Errorf("failed to get block hash at height %d: %w", r.next, err)
+		}
+		if err := r.scanHash(hash); err != nil {
+			return fmt.Errorf("failed to scan block %s at height %d: %w", hash, r.next, err)
+		}
+	}
+	return nil
+}
+
+// refresh updates the confirmations of stored anchors that are not final
+// yet. Anchors whose block left the active chain are marked with -1 and the
+// chain is scanned again from that height, where a replacement block may
+// hold them.
+func (r *blockReader) refresh() {
+	anchors, err := models.GetUnsettledBTCAnchors(r.finalDepth)
+	if err != nil {
+		utils.LogDatabaseError("BTCReader", "Failed to get unsettled anchors", err, nil)
+		return
+	}
+
+	checked := map[string]bool{}
+	for _, anchor := range anchors {
+		if checked[anchor.BlockHash] {
+			continue
+		}
+		checked[anchor.BlockHash] = true
+
+		header, err := GetBlockHeader(anchor.BlockHash)
+		if err != nil {
+			utils.LogNetworkError("BTCReader", "Failed to get block header", err, map[string]interface{}{
+				"hash": anchor.BlockHash,
+			})
+			return
+		}
+
+		confirmations := header.Confirmations
+		if confirmations < 0 {
+			log.Printf("Block %s at height %d left the active chain, orphaning its anchors", header.Hash, header.Height)
+			confirmations = -1
+			if header.Height < r.next {
+				r.next = header.Height
+			}
+		}
+		if confirmations == anchor.Confirmations {
+			continue
+		}
+		if err := models.SetBTCAnchorConfirmations(anchor.BlockHash, confirmations); err != nil {
+			utils.LogDatabaseError("BTCReader", "Failed to update anchor confirmations", err, map[string]interface{}{
+				"hash": anchor.BlockHash,
+			})
+			return
+		}
+	}
+}
+
+func (r *blockReader) subscribe(cfg *config.Config) {
+	endpoint, topic := cfg.ZmqEndpointRawBlock, "rawblock"
+	if endpoint == "" {
+		endpoint, topic = cfg.ZmqEndpointHashBlock, "hashblock"
+	}
+
+	subscriber := NewBlockSubscriber()
+	defer func() {
+		if err := subscriber.Close(); err != nil {
+			log.Printf("Error closing BlockSubscriber: %v", err)
+		}
+	}()
+
+	if !subscriber.Subscribe(endpoint, topic) {
+		log.Fatalf("Failed to subscribe to %s on %s after retries", topic, endpoint)
+	}
+
+	// Blocks mined while we were offline are not replayed by ZMQ
+	if err := r.catchUp(); err != nil {
+		utils.LogProcessingError("BTCReader", "Failed to catch up", err, map[string]interface{}{
+			"height": r.next,
+		})
+	}
+
+	for {
+		ok, msg := subscriber.GetMessage()
+		if !subscriber.Validate(ok, msg) {
+			time.Sleep(1 * time.Second)
+			continue
+		}
+
+		var err error
+		switch string(msg[0]) {
+		case "rawblock":
+			err = r.scanRaw(msg[1])
+		case "hashblock":
+			err = r.scanHash(hex.EncodeToString(msg[1]))
+		default:
+			continue
+		}
+		if err != nil {
+			utils.LogProcessingError("BTCReader", "Failed to scan notified block", err, map[string]interface{}{
+				"topic": string(msg[0]),
+			})
+		}
+		r.refresh()
+	}
+}
+
+func (r *blockReader) scanHash(hash string) error {
+	raw, err := GetRawBlock(hash)
+	if err != nil {
+		return err
+	}
+	return r.scanRaw(raw)
+}
+
+// scanRaw decodes a serialized block and records its anchors. Any gap
+// between the last scanned height and this block is filled in first; the
+// block is left for the next catch up if the gap cannot be filled.
+func (r *blockReader) scanRaw(raw []byte) error {
+	var block wire.MsgBlock
+	if err := block.Deserialize(bytes.NewReader(raw)); err != nil {
+		return fmt.Errorf("failed to deserialize block: %w", err)
+	}
+
+	hash := block.BlockHash().String()
+	header, err := GetBlockHeader(hash)
+	if err != nil {
+		return err
+	}
+	if header.Confirmations < 0 {
+		log.Printf("Block %s is not in the active chain, skipping", hash)
+		return nil
+	}
+
+	if header.Height > r.next {
+		if err := r.catchUp(); err != nil {
+			return fmt.Errorf("block %d left for the next catch up: %w", header.Height, err)
+		}
+		if r.next > header.Height {
+			return nil // scanned while catching up
+		}
+		if r.next < header.Height {
+			return fmt.Errorf("block %d left for the next catch up: scanned up to height %d", header.Height, r.next-1)
+		}
+	}
+
+	for _, anchor := range FindAnchors(&block, r.protocolId) {
+		err := models.UpsertBTCAnchor(&models.BTCAnchor{
+			TxHash:        anchor.TxHash,
+			Vout:          anchor.Vout,
+			BlockHash:     header.Hash,
+			BlockHeight:   header.Height,
+			BlockTime:     time.Unix(header.Time, 0).UTC(),
+			Confirmations: header.Confirmations,
+			Payload:       anchor.Payload,
+			Root:          anchor.Root,
+		})
+		if err != nil {
+			return err
+		}
+		if anchor.Chunk != nil {
+			log.Printf("Found anchor part %d of %d %s:%d in block %d", anchor.Chunk.Index+1, anchor.Chunk.Total, anchor.TxHash, anchor.Vout, header.Height)
+			if err := r.joinChunks(anchor.Chunk); err != nil {
+				return err
+			}
+			continue
+		}
+		log.Printf("Found anchor %s:%d in block %d: %s", anchor.TxHash, anchor.Vout, header.Height, anchor.Root)
+	}
+
+	if header.Height >= r.next {
+		r.next = header.Height + 1
+	}
+	return nil
+}
+
+// joinChunks reassembles a split payload once every part of chunk's group
+// has been seen, possibly across blocks, and records the root on all parts
+func (r *blockReader) joinChunks(chunk *AnchorPayload) error {
+	prefix := ChunkPrefix(r.protocolId, chunk.Group)
+	rows, err := models.GetBTCAnchorsByPayloadPrefix(prefix)
+	if err != nil {
+		return err
+	}
+
+	chunks := []*AnchorPayload{}
+	seen := map[int]bool{}
+	for _, row := range rows {
+		part, err := ParseAnchorPayload(r.protocolId, row.Payload)
+		if err != nil || seen[part.Index] {
+			continue
+		}
+		seen[part.Index] = true
+		chunks = append(chunks, part)
+	}
+	if len(chunks) < chunk.Total {
+		return nil // waiting for the remaining parts
+	}
+
+	payload, err := JoinAnchorChunks(r.protocolId, chunks)
+	if err != nil {
+		return err
+	}
+	if err := models.SetBTCAnchorRootByPayloadPrefix(prefix, payload.Root()); err != nil {
+		return err
+	}
+	log.Printf("Reassembled split anchor %x from %d parts: %s", chunk.Group, chunk.Total, payload.Root())
+	return nil
+}
@@ -0,0 +1,223 @@
+package da
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// BlockHeader is the subset of getblockheader used by the reader and tracker
+type BlockHeader struct {
+	Hash              string `json:"hash"`
+	Confirmations     int64  `json:"confirmations"`
+	Height            int64  `json:"height"`
+	Time              int64  `json:"time"`
+	PreviousBlockHash string `json:"previousblockhash"`
+}
+
+// callRPC sends a JSON-RPC request to bitcoind with retries and returns the extracted result
+func callRPC(id string, method string, params []interface{}) (string, error) {
+	jsonPayload, err := rpcPayload(id, method, params)
+	if err != nil {
+		return "", err
+	}
+
+	result, err := RetryRPCCall(func() (string, error) {
+		return makeRPCCallWithTimeout(BTCEndpoint, jsonPayload)
+	})
+	if err != nil {
+		return "", fmt.Errorf("%s RPC call failed: %w", method, err)
+	}
+	if result == "" || result == "null" {
+		return "", fmt.Errorf("%s RPC call returned no result", method)
+	}
+
+	return result, nil
+}
+
+// callRPCOnce sends a single JSON-RPC request without retries. It is used
+// for lookups where an RPC error is an expected answer and must not count
+// against the RPC circuit breaker.
+func callRPCOnce(id string, method string, params []interface{}) (string, error) {
+	jsonPayload, err := rpcPayload(id, method, params)
+	if err != nil {
+		return "", err
+	}
+
+	result, err := makeRPCCallWithTimeout(BTCEndpoint, jsonPayload)
+	if err != nil {
+		return "", fmt.Errorf("%s RPC call failed: %w", method, err)
+	}
+	if result == "" || result == "null" {
+		return "", fmt.Errorf("%s RPC call returned no result", method)
+	}
+
+	return result, nil
+}
+
+func rpcPayload(id string, method string, params []interface{}) ([]byte, error) {
+	payload := map[string]interface{}{
+		"jsonrpc": "1.0",
+		"id":      id,
+		"method":  method,
+		"params":  params,
+	}
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s payload: %w", method, err)
+	}
+	return jsonPayload, nil
+}
+
+// GetBlockCount returns the height of the most-work fully-validated chain
+func GetBlockCount() (int64, error) {
+	result, err := callRPC("get_block_count", "getblockcount", []interface{}{})
+	if err != nil {
+		return 0, err
+	}
+
+	height, err := strconv.ParseInt(strings.TrimSpace(result), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid getblockcount result %q: %w", result, err)
+	}
+	return height, nil
+}
+
+// GetBlockHash returns the hash of the block at height in the active chain
+func GetBlockHash(height int64) (string, error) {
+	return callRPC("get_block_hash", "getblockhash", []interface{}{height})
+}
+
+// GetBlockHeader returns height, time and confirmation data for a block
+func GetBlockHeader(hash string) (*BlockHeader, error) {
+	result, err := callRPC("get_block_header", "getblockheader", []interface{}{hash, true})
+	if err != nil {
+		return nil, err
+	}
+
+	var header BlockHeader
+	if err := json.Unmarshal([]byte(result), &header); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal block header: %w", err)
+	}
+	return &header, nil
+}
+
+// GetRawBlock returns the serialized block for hash
+func GetRawBlock(hash string) ([]byte, error) {
+	result, err := callRPC("get_raw_block", "getblock", []interface{}{hash, 0})
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := hex.DecodeString(strings.TrimSpace(result))
+	if err != nil {
+		return nil, fmt.Errorf("invalid raw block hex: %w", err)
+	}
+	return raw, nil
+}
+
+// WalletTransaction is the subset of gettransaction used to track anchors.
+// Negative confirmations mean the transaction conflicted that many blocks ago.
+type WalletTransaction struct {
+	Txid            string   `json:"txid"`
+	Confirmations   int64    `json:"confirmations"`
+	Blockhash       string   `json:"blockhash,omitempty"`
+	Blockheight     *int64   `json:"blockheight,omitempty"`
+	Blocktime       *int64   `json:"blocktime,omitempty"`
+	WalletConflicts []string `json:"walletconflicts"`
+	Hex             string   `json:"hex"`
+}
+
+// GetWalletTransaction returns wallet information about txHash
+func GetWalletTransaction(txHash string) (*WalletTransaction, error) {
+	result, err := callRPC("get_tx_info", "gettransaction", []interface{}{txHash})
+	if err != nil {
+		return nil, err
+	}
+
+	var tx WalletTransaction
+	if err := json.Unmarshal([]byte(result), &tx); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal transaction: %w", err)
+	}
+	return &tx, nil
+}
+
+// MempoolEntry is the subset of getmempoolentry used by the fee bumper
+type MempoolEntry struct {
+	Vsize           int64 `json:"vsize"`
+	Time            int64 `json:"time"`
+	DescendantCount int64 `json:"descendantcount"`
+	DescendantSize  int64 `json:"descendantsize"`
+	Fees            struct {
+		Base       float64 `json:"base"`
+		Descendant float64 `json:"descendant"`
+	} `json:"fees"`
+	BIP125Replaceable bool `json:"bip125-replaceable"`
+}
+
+// GetMempoolEntry returns mempool data for txHash, or an error if it is not in the mempool
+func GetMempoolEntry(txHash string) (*MempoolEntry, error) {
+	result, err := callRPCOnce("get_mempool_entry", "getmempoolentry", []interface{}{txHash})
+	if err != nil {
+		return nil, err
+	}
+
+	var entry MempoolEntry
+	if err := json.Unmarshal([]byte(result), &entry); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal mempool entry: %w", err)
+	}
+	return &entry, nil
+}
+
+// rpcInvalidAddressOrKey is the RPC error code getmempoolentry returns for
+// transactions that are not in the mempool
+const rpcInvalidAddressOrKey = -5
+
+// InMempool reports whether txHash is currently in the node's mempool. Any
+// error other than the node answering that it is not there is returned.
+func InMempool(txHash string) (bool, error) {
+	_, err := GetMempoolEntry(txHash)
+	if err == nil {
+		return true, nil
+	}
+	var rpcErr *RPCError
+	if errors.As(err, &rpcErr) && rpcErr.Code == rpcInvalidAddressOrKey {
+		return false, nil
+	}
+	return false, err
+}
+
+// BumpFeeResult is the result of the wallet's bumpfee call
+type BumpFeeResult struct {
+	Txid    string   `json:"txid"`
+	OrigFee float64  `json:"origfee"`
+	Fee     float64  `json:"fee"`
+	Errors  []string `json:"errors"`
+}
+
+// BumpFee replaces txHash with a BIP125 replacement paying feeRate sat/vB.
+// It is not retried since a rejected bump is an expected outcome.
+func BumpFee(txHash string, feeRate float64) (*BumpFeeResult, error) {
+	result, err := callRPCOnce("bump_fee", "bumpfee", []interface{}{
+		txHash,
+		map[string]interface{}{
+			"fee_rate":    feeRate,
+			"replaceable": true,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var bump BumpFeeResult
+	if err := json.Unmarshal([]byte(result), &bump); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal bumpfee result: %w", err)
+	}
+	if len(bump.Errors) > 0 {
+		return nil, fmt.Errorf("bumpfee reported errors: %s", strings.Join(bump.Errors, "; "))
+	}
+	return &bump, nil
+}
@@ -0,0 +1,228 @@
+package da
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+
+	"github.com/Layer-Edge/bitcoin-da/config"
+)
+
+const (
+	CoinSelectionBranchAndBound = "branch-and-bound"
+	CoinSelectionLargestFirst   = "largest-first"
+	CoinSelectionPrivacy        = "privacy"
+
+	// bnbMaxTries bounds the branch-and-bound search, as in Bitcoin Core
+	bnbMaxTries = 100000
+	// changeSpendVsize is the cost of later spending a P2WPKH change output
+	changeSpendVsize = 68
+)
+
+// coinSelectionStrategy is set from `coin-selection.strategy` in the config
+var coinSelectionStrategy = CoinSelectionBranchAndBound
+
+// walletSpendMutex serialises selecting and broadcasting wallet spends so
+// that concurrent jobs do not pick the same UTXOs
+var walletSpendMutex sync.Mutex
+
+// CoinSelection is the set of inputs chosen to fund an anchor. Change is
+// zero when no change output is needed, in which case the excess goes to fees.
+type CoinSelection struct {
+	Inputs []utxo
+	Total  int64 // sats
+	Fee    int64 // sats
+	Change int64 // sats
+}
+
+// InitCoinSelection loads the coin selection strategy from the config
+func InitCoinSelection(cfg *config.Config) {
+	coinSelectionStrategy = cfg.CoinSelection.Strategy
+}
+
+// ParseUTXOs decodes a listunspent result
+func ParseUTXOs(unspent string) ([]utxo, error) {
+	var utxos []utxo
+	if err := json.Unmarshal([]byte(unspent), &utxos); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal listunspent result: %w", err)
+	}
+	return utxos, nil
+}
+
+// SelectCoins picks inputs to fund an anchor carrying dataSize bytes of
+// OP_RETURN data at feeRate sat/vB using the given strategy
+func SelectCoins(strategy string, utxos []utxo, dataSize int, feeRate float64) (*CoinSelection, error) {
+	if len(utxos) == 0 {
+		return nil, fmt.Errorf("wallet has no spendable UTXOs")
+	}
+
+	switch strategy {
+	case CoinSelectionBranchAndBound:
+		if sel := selectBranchAndBound(utxos, dataSize, feeRate); sel != nil {
+			return sel, nil
+		}
+		// No changeless match, fall back to a selection with change
+		return selectLargestFirst(utxos, dataSize, feeRate)
+	case CoinSelectionLargestFirst:
+		return selectLargestFirst(utxos, dataSize, feeRate)
+	case CoinSelectionPrivacy:
+		return selectPrivacy(utxos, dataSize, feeRate)
+	default:
+		return nil, fmt.Errorf("unknown coin selection strategy %q", strategy)
+	}
+}
+
+// inputFee returns the fee in sats for spending u at feeRate
+func inputFee(u utxo, feeRate float64) int64 {
+	return int64(math.Ceil(EstimateInputVsize(u) * feeRate))
+}
+
+// baseFee returns the fee in sats for an anchor without inputs or change
+func baseFee(dataSize int, feeRate float64) int64 {
+	return FeeForVsize(int64(math.Ceil(txOverheadVsize))+opReturnOutputVsize(dataSize), feeRate)
+}
+
+// selectBranchAndBound searches for a set of inputs whose effective value
+// covers the fee without leaving enough excess to be worth a change output.
+// It returns nil if no such set exists.
+func selectBranchAndBound(utxos []utxo, dataSize int, feeRate float64) *CoinSelection {
+	target := baseFee(dataSize, feeRate)
+	costOfChange := int64(math.Ceil(float64(changeOutputVsize+changeSpendVsize) * feeRate))
+
+	type candidate struct {
+		u         utxo
+		effective int64
+	}
+	candidates := []candidate{}
+	available := int64(0)
+	for _, u := range utxos {
+		effective := btcToSats(u.Amount) - inputFee(u, feeRate)
+		if effective <= 0 {
+			continue // costs more to spend than it is worth
+		}
+		candidates = append(candidates, candidate{u, effective})
+		available += effective
+	}
+	if available < target {
+		return nil
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].effective > candidates[j].effective })
+
+	// remaining[i] is the effective value of candidates[i:]
+	remaining := make([]int64, len(candidates)+1)
+	for i := len(candidates) - 1; i >= 0; i-- {
+		remaining[i] = remaining[i+1] + candidates[i].effective
+	}
+
+	var best []int
+	bestWaste := int64(math.MaxInt64)
+	selected := []int{}
+	tries := 0
+
+	var search func(depth int, value int64)
+	search = func(depth int, value int64) {
+		tries++
+		if tries > bnbMaxTries || value > target+costOfChange || value+remaining[depth] < target {
+			return
+		}
+		if value >= target {
+			if waste := value - target; waste < bestWaste {
+				bestWaste = waste
+				best = append([]int(nil), selected...)
+			}
+			return
+		}
+		if depth == len(candidates) {
+			return
+		}
+
+		selected = append(selected, depth)
+		search(depth+1, value+candidates[depth].effective)
+		selected = selected[:len(selected)-1]
+
+		// Skipping an input equal to the one just tried yields the same sets
+		next := depth + 1
+		for next < len(candidates) && candidates[next].effective == candidates[depth].effective {
+			next++
+		}
+		search(next, value)
+	}
+	search(0, 0)
+
+	if best == nil {
+		return nil
+	}
+
+	sel := &CoinSelection{}
+	for _, i := range best {
+		sel.Inputs = append(sel.Inputs, candidates[i].u)
+		sel.Total += btcToSats(candidates[i].u.Amount)
+	}
+	sel.Fee = sel.Total
+	log.Printf("Branch-and-bound selected %d inputs without change (excess %d sats)", len(sel.Inputs), bestWaste)
+	return sel
+}
+
+// selectLargestFirst adds the largest UTXOs until the fee is covered
+func selectLargestFirst(utxos []utxo, dataSize int, feeRate float64) (*CoinSelection, error) {
+	sorted := append([]utxo(nil), utxos...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Amount > sorted[j].Amount })
+
+	sel := &CoinSelection{}
+	for _, u := range sorted {
+		sel.Inputs = append(sel.Inputs, u)
+		sel.Total += btcToSats(u.Amount)
+		if finishSelection(sel, dataSize, feeRate) {
+			return sel, nil
+		}
+	}
+	return nil, fmt.Errorf("UTXOs total %d sats, not enough to fund the anchor at %.2f sat/vB", sel.Total, feeRate)
+}
+
+// selectPrivacy funds the anchor from a single address where possible so
+// that the transaction does not link the wallet's addresses together
+func selectPrivacy(utxos []utxo, dataSize int, feeRate float64) (*CoinSelection, error) {
+	groups := map[string][]utxo{}
+	for _, u := range utxos {
+		groups[u.Address] = append(groups[u.Address], u)
+	}
+
+	// Try addresses in random order so anchors do not always drain the same one
+	addresses := make([]string, 0, len(groups))
+	for address := range groups {
+		addresses = append(addresses, address)
+	}
+	sort.Strings(addresses)
+	rand.Shuffle(len(addresses), func(i, j int) { addresses[i], addresses[j] = addresses[j], addresses[i] })
+
+	for _, address := range addresses {
+		if sel, err := selectLargestFirst(groups[address], dataSize, feeRate); err == nil {
+			return sel, nil
+		}
+	}
+
+	log.Printf("No single address can fund the anchor, mixing inputs from %d addresses", len(groups))
+	return selectLargestFirst(utxos, dataSize, feeRate)
+}
+
+// finishSelection sets the fee and change for sel's inputs. It reports false
+// if the inputs do not cover the fee yet. Change below the dust limit is
+// dropped and added to the fee.
+func finishSelection(sel *CoinSelection, dataSize int, feeRate float64) bool {
+	feeWithChange := FeeForVsize(EstimateTxVsize(sel.Inputs, dataSize), feeRate)
+	if change := sel.Total - feeWithChange; change >= dustLimitSats {
+		sel.Fee, sel.Change = feeWithChange, change
+		return true
+	}
+
+	feeWithoutChange := FeeForVsize(EstimateTxVsize(sel.Inputs, dataSize)-changeOutputVsize, feeRate)
+	if sel.Total >= feeWithoutChange {
+		sel.Fee, sel.Change = sel.Total, 0
+		return true
+	}
+	return false
+}
@@ -0,0 +1,310 @@
+package da
+
+import (
+	"sort"
+	"testing"
+)
+
+const (
+	testDataSize = 40
+	testFeeRate  = 10.0
+)
+
+// coin returns a P2WPKH UTXO of sats at address
+func coin(txid string, address string, sats int64) utxo {
+	return utxo{
+		Txid:         txid,
+		Amount:       satsToBTC(sats),
+		Address:      address,
+		ScriptPubKey: "0014" + "1111111111111111111111111111111111111111",
+	}
+}
+
+// effectiveCoin returns a UTXO worth effective sats once the fee for
+// spending it at testFeeRate is paid
+func effectiveCoin(txid string, effective int64) utxo {
+	u := coin(txid, "addr", 0)
+	u.Amount = satsToBTC(effective + inputFee(u, testFeeRate))
+	return u
+}
+
+func txids(inputs []utxo) []string {
+	ids := make([]string, len(inputs))
+	for i, u := range inputs {
+		ids[i] = u.Txid
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func sameTxids(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// checkFees verifies that sel's fee and change follow FeeForVsize for its
+// inputs: a selection with change pays exactly the fee of the transaction
+// with a change output, a changeless one pays at least the fee without it
+func checkFees(t *testing.T, sel *CoinSelection) {
+	t.Helper()
+
+	total := int64(0)
+	for _, u := range sel.Inputs {
+		total += btcToSats(u.Amount)
+	}
+	if sel.Total != total {
+		t.Errorf("total = %d, inputs hold %d", sel.Total, total)
+	}
+	if sel.Total != sel.Fee+sel.Change {
+		t.Errorf("total %d != fee %d + change %d", sel.Total, sel.Fee, sel.Change)
+	}
+
+	vsize := EstimateTxVsize(sel.Inputs, testDataSize)
+	if sel.Change > 0 {
+		if sel.Change < dustLimitSats {
+			t.Errorf("change %d is below the dust limit", sel.Change)
+		}
+		if want := FeeForVsize(vsize, testFeeRate); sel.Fee != want {
+			t.Errorf("fee with change = %d, want %d", sel.Fee, want)
+		}
+		return
+	}
+	if min := FeeForVsize(vsize-changeOutputVsize, testFeeRate); sel.Fee < min {
+		t.Errorf("changeless fee = %d, below the %d needed", sel.Fee, min)
+	}
+}
+
+func TestSelectBranchAndBound(t *testing.T) {
+	target := baseFee(testDataSize, testFeeRate)
+	costOfChange := FeeForVsize(changeOutputVsize+changeSpendVsize, testFeeRate)
+
+	tests := []struct {
+		name  string
+		utxos []utxo
+		want  []string // nil when there is no changeless match
+	}{
+		{
+			name:  "exact match",
+			utxos: []utxo{effectiveCoin("a", target)},
+			want:  []string{"a"},
+		},
+		{
+			name:  "excess inside the cost of change",
+			utxos: []utxo{effectiveCoin("a", target+costOfChange-1)},
+			want:  []string{"a"},
+		},
+		{
+			name:  "excess above the cost of change",
+			utxos: []utxo{effectiveCoin("a", target+costOfChange+1)},
+		},
+		{
+			name: "two inputs match beside a large one",
+			utxos: []utxo{
+				effectiveCoin("big", 20*target),
+				effectiveCoin("b", target/2),
+				effectiveCoin("c", target-target/2),
+			},
+			want: []string{"b", "c"},
+		},
+		{
+			name: "least waste wins",
+			utxos: []utxo{
+				effectiveCoin("a", target+costOfChange/2),
+				effectiveCoin("b", target+10),
+			},
+			want: []string{"b"},
+		},
+		{
+			name:  "insufficient funds",
+			utxos: []utxo{effectiveCoin("a", target/2), effectiveCoin("b", target/4)},
+		},
+		{
+			name:  "uneconomic UTXOs are skipped",
+			utxos: []utxo{coin("dust", "addr", inputFee(coin("", "", 0), testFeeRate)), effectiveCoin("a", target/2)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sel := selectBranchAndBound(tt.utxos, testDataSize, testFeeRate)
+			if tt.want == nil {
+				if sel != nil {
+					t.Fatalf("selected %v, want no changeless match", txids(sel.Inputs))
+				}
+				return
+			}
+			if sel == nil {
+				t.Fatalf("no selection, want %v", tt.want)
+			}
+			if got := txids(sel.Inputs); !sameTxids(got, tt.want) {
+				t.Errorf("selected %v, want %v", got, tt.want)
+			}
+			if sel.Change != 0 {
+				t.Errorf("change = %d, want none", sel.Change)
+			}
+			checkFees(t, sel)
+
+			vsize := EstimateTxVsize(sel.Inputs, testDataSize) - changeOutputVsize
+			if excess := sel.Fee - FeeForVsize(vsize, testFeeRate); excess > costOfChange {
+				t.Errorf("excess %d is above the cost of change %d", excess, costOfChange)
+			}
+		})
+	}
+}
+
+func TestSelectCoins(t *testing.T) {
+	target := baseFee(testDataSize, testFeeRate)
+
+	tests := []struct {
+		name     string
+		strategy string
+		utxos    []utxo
+		want     []string
+		change   bool
+		err      bool
+	}{
+		{
+			name:     "branch-and-bound takes a changeless match",
+			strategy: CoinSelectionBranchAndBound,
+			utxos:    []utxo{effectiveCoin("big", 20*target), effectiveCoin("a", target)},
+			want:     []string{"a"},
+		},
+		{
+			name:     "branch-and-bound falls back to largest first",
+			strategy: CoinSelectionBranchAndBound,
+			utxos:    []utxo{effectiveCoin("small", target/2), effectiveCoin("big", 20*target)},
+			want:     []string{"big"},
+			change:   true,
+		},
+		{
+			name:     "fallback adds inputs until the fee is covered",
+			strategy: CoinSelectionBranchAndBound,
+			utxos:    []utxo{effectiveCoin("a", 3*target), effectiveCoin("b", 3*target), effectiveCoin("c", 100)},
+			want:     []string{"a"},
+			change:   true,
+		},
+		{
+			name:     "branch-and-bound with insufficient funds",
+			strategy: CoinSelectionBranchAndBound,
+			utxos:    []utxo{effectiveCoin("a", target/4)},
+			err:      true,
+		},
+		{
+			name:     "largest first",
+			strategy: CoinSelectionLargestFirst,
+			utxos:    []utxo{effectiveCoin("a", target), effectiveCoin("big", 20*target)},
+			want:     []string{"big"},
+			change:   true,
+		},
+		{
+			name:     "largest first drops dust change into the fee",
+			strategy: CoinSelectionLargestFirst,
+			utxos:    []utxo{effectiveCoin("a", target+dustLimitSats/2)},
+			want:     []string{"a"},
+		},
+		{
+			name:     "no UTXOs",
+			strategy: CoinSelectionLargestFirst,
+			err:      true,
+		},
+		{
+			name:     "unknown strategy",
+			strategy: "smallest-first",
+			utxos:    []utxo{effectiveCoin("a", 20*target)},
+			err:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sel, err := SelectCoins(tt.strategy, tt.utxos, testDataSize, testFeeRate)
+			if tt.err {
+				if err == nil {
+					t.Fatalf("selected %v, want an error", txids(sel.Inputs))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SelectCoins: %v", err)
+			}
+			if got := txids(sel.Inputs); !sameTxids(got, tt.want) {
+				t.Errorf("selected %v, want %v", got, tt.want)
+			}
+			if (sel.Change > 0) != tt.change {
+				t.Errorf("change = %d, want change %v", sel.Change, tt.change)
+			}
+			checkFees(t, sel)
+		})
+	}
+}
+
+func TestSelectPrivacy(t *testing.T) {
+	target := baseFee(testDataSize, testFeeRate)
+	spend := inputFee(coin("", "", 0), testFeeRate)
+
+	tests := []struct {
+		name      string
+		utxos     []utxo
+		addresses int    // distinct addresses among the inputs
+		avoid     string // address that cannot fund the anchor alone
+		want      []string
+	}{
+		{
+			name: "only one address can fund the anchor",
+			utxos: []utxo{
+				coin("a1", "a", 5*target), coin("a2", "a", 5*target),
+				coin("b1", "b", target/2),
+			},
+			addresses: 1,
+			avoid:     "b",
+		},
+		{
+			name: "every address can fund the anchor",
+			utxos: []utxo{
+				coin("a1", "a", 5*target),
+				coin("b1", "b", 5*target),
+				coin("c1", "c", 5*target),
+			},
+			addresses: 1,
+		},
+		{
+			name: "no single address can fund the anchor",
+			utxos: []utxo{
+				coin("a1", "a", target+3*spend/4),
+				coin("b1", "b", target+3*spend/4),
+			},
+			addresses: 2,
+			want:      []string{"a1", "b1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Addresses are tried in random order
+			for i := 0; i < 20; i++ {
+				sel, err := SelectCoins(CoinSelectionPrivacy, tt.utxos, testDataSize, testFeeRate)
+				if err != nil {
+					t.Fatalf("SelectCoins: %v", err)
+				}
+				addresses := map[string]bool{}
+				for _, u := range sel.Inputs {
+					addresses[u.Address] = true
+				}
+				if len(addresses) != tt.addresses {
+					t.Fatalf("inputs %v span %d addresses, want %d", txids(sel.Inputs), len(addresses), tt.addresses)
+				}
+				if tt.avoid != "" && addresses[tt.avoid] {
+					t.Fatalf("selected address %s, which cannot fund the anchor", tt.avoid)
+				}
+				if tt.want != nil && !sameTxids(txids(sel.Inputs), tt.want) {
+					t.Fatalf("selected %v, want %v", txids(sel.Inputs), tt.want)
+				}
+				checkFees(t, sel)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package da
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Layer-Edge/bitcoin-da/config"
+	"github.com/Layer-Edge/bitcoin-da/models"
+	"github.com/Layer-Edge/bitcoin-da/utils"
+)
+
+// BTCConfirmationTracker polls every anchored super proof until its BTC
+// transaction reaches the required number of confirmations, backfilling the
+// block hash, height and time. Anchors that were reorged out, conflicted or
+// dropped from the mempool are re-queued for NonBTCTxSuperProofCronJob.
+func BTCConfirmationTracker(cfg *config.Config) {
+	err := models.InitDB(cfg.PostgresConnectionURI)
+	if err != nil {
+		log.Fatalf("Error initializing DB Connection: %v", err)
+	}
+	defer func() {
+		if err := models.CloseDB(); err != nil {
+			log.Printf("Error closing database: %v", err)
+		}
+	}()
+
+	InitOPReturnRPC(cfg.BtcEndpoint, cfg.Auth, cfg.WalletPassphrase)
+
+	interval := time.Duration(cfg.ConfirmationTracker.PollIntervalSeconds) * time.Second
+	log.Printf("Starting BTC confirmation tracker (required confirmations: %d, interval: %v)",
+		cfg.ConfirmationTracker.RequiredConfirmations, interval)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		trackSuperProofConfirmations(cfg)
+		<-ticker.C
+	}
+}
+
+func trackSuperProofConfirmations(cfg *config.Config) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Panic in trackSuperProofConfirmations: %v", r)
+		}
+	}()
+
+	proofs, err := models.GetUnconfirmedSuperProofs()
+	if err != nil {
+		utils.LogDatabaseError("ConfirmationTracker", "Failed to fetch unconfirmed super proofs", err, nil)
+		return
+	}
+
+	if len(proofs) == 0 {
+		return
+	}
+
+	log.Printf("Tracking confirmations for %d super proofs", len(proofs))
+	for i := range proofs {
+		trackSuperProof(cfg, &proofs[i])
+	}
+}
+
+func trackSuperProof(cfg *config.Config, proof *models.AggregatedProof) {
+	txHash := *proof.BTCTxHash
+
+	tx, err := GetWalletTransaction(txHash)
+	if err != nil {
+		utils.LogNetworkError("ConfirmationTracker", "Failed to get anchor transaction", err, map[string]interface{}{
+			"super_proof": proof.ID,
+			"btc_tx_hash": txHash,
+		})
+		return
+	}
+
+	lost, err := anchorLost(tx)
+	if err != nil {
+		// Without a clear answer from the node the anchor is left alone, so
+		// a failed RPC call cannot anchor the root twice
+		utils.LogNetworkError("ConfirmationTracker", "Failed to check whether anchor transaction is lost", err, map[string]interface{}{
+			"super_proof": proof.ID,
+			"btc_tx_hash": txHash,
+		})
+		return
+	}
+	if lost {
+		utils.LogBlockchainError("ConfirmationTracker", "Anchor transaction lost, re-queueing super proof",
+			fmt.Errorf("anchor %s can no longer confirm", txHash), map[string]interface{}{
+				"super_proof":      proof.ID,
+				"btc_tx_hash":      txHash,
+				"confirmations":    tx.Confirmations,
+				"wallet_conflicts": tx.WalletConflicts,
+			})
+		if err := models.RequeueSuperProofForAnchoring(proof.ID); err != nil {
+			utils.LogDatabaseError("ConfirmationTracker", "Failed to re-queue super proof", err, map[string]interface{}{
+				"super_proof": proof.ID,
+			})
+		}
+		return
+	}
+
+	if tx.Confirmations == 0 {
+		// Still in the mempool. If we had recorded a block, it was reorged out.
+		if proof.BTCBlockHash != nil {
+			log.Printf("Super proof %s anchor %s was reorged out of block %s", proof.ID, txHash, *proof.BTCBlockHash)
+			if err := models.UpdateSuperProofConfirmation(proof.ID, nil, nil, nil, 0, false); err != nil {
+				utils.LogDatabaseError("ConfirmationTracker", "Failed to clear reorged block data", err, map[string]interface{}{
+					"super_proof": proof.ID,
+				})
+			}
+		}
+		return
+	}
+
+	var blockTime *time.Time
+	if tx.Blocktime != nil {
+		t := time.Unix(*tx.Blocktime, 0).UTC()
+		blockTime = &t
+	}
+	blockHash := tx.Blockhash
+	confirmed := tx.Confirmations >= cfg.ConfirmationTracker.RequiredConfirmations
+
+	err = models.UpdateSuperProofConfirmation(proof.ID, &blockHash, tx.Blockheight, blockTime, tx.Confirmations, confirmed)
+	if err != nil {
+		utils.LogDatabaseError("ConfirmationTracker", "Failed to record anchor confirmation", err, map[string]interface{}{
+			"super_proof": proof.ID,
+		})
+		return
+	}
+
+	if confirmed {
+		log.Printf("Super proof %s anchor %s confirmed in block %s (%d confirmations)", proof.ID, txHash, blockHash, tx.Confirmations)
+	}
+}
+
+// anchorLost reports whether the anchor transaction can no longer confirm:
+// it conflicted with a mined transaction (negative confirmations or a
+// confirmed wallet conflict), or it is unconfirmed and gone from the mempool.
+// An error means the node could not tell.
+func anchorLost(tx *WalletTransaction) (bool, error) {
+	if tx.Confirmations < 0 {
+		return true, nil
+	}
+	if tx.Confirmations > 0 {
+		return false, nil
+	}
+
+	for _, conflict := range tx.WalletConflicts {
+		other, err := GetWalletTransaction(conflict)
+		if err == nil && other.Confirmations > 0 {
+			return true, nil
+		}
+		// A fee-bumped replacement is still pending; the fee bumper
+		// moves the super proof over to it
+		pending, err := InMempool(conflict)
+		if err != nil {
+			return false, err
+		}
+		if pending {
+			return false, nil
+		}
+	}
+
+	pending, err := InMempool(tx.Txid)
+	if err != nil {
+		return false, err
+	}
+	return !pending, nil
+}
@@ -0,0 +1,142 @@
+package da
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/Layer-Edge/bitcoin-da/config"
+	"github.com/Layer-Edge/bitcoin-da/utils"
+)
+
+// UTXOConsolidationJob merges the wallet's smallest UTXOs into a single
+// output on a fresh address whenever the wallet holds more than
+// coin-selection.consolidation.min-utxos and the fee rate is below
+// coin-selection.consolidation.max-fee-rate
+func UTXOConsolidationJob(cfg *config.Config) {
+	InitOPReturnRPC(cfg.BtcEndpoint, cfg.Auth, cfg.WalletPassphrase)
+	InitFeeEstimation(cfg)
+
+	interval := time.Duration(cfg.CoinSelection.Consolidation.PollIntervalSeconds) * time.Second
+	log.Printf("Starting UTXO consolidation (min %d UTXOs, max %d inputs, max %.1f sat/vB)",
+		cfg.CoinSelection.Consolidation.MinUTXOs, cfg.CoinSelection.Consolidation.MaxInputs,
+		cfg.CoinSelection.Consolidation.MaxFeeRate)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		consolidateUTXOs(cfg)
+		<-ticker.C
+	}
+}
+
+func consolidateUTXOs(cfg *config.Config) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Panic in consolidateUTXOs: %v", r)
+		}
+	}()
+
+	walletSpendMutex.Lock()
+	defer walletSpendMutex.Unlock()
+
+	unspent := ListUnspent()
+	if unspent == "" {
+		return
+	}
+	utxos, err := ParseUTXOs(unspent)
+	if err != nil {
+		utils.LogProcessingError("UTXOConsolidation", "Failed to parse UTXOs", err, nil)
+		return
+	}
+	if len(utxos) < cfg.CoinSelection.Consolidation.MinUTXOs {
+		return
+	}
+
+	feeRate := CurrentFeeRate()
+	if feeRate > cfg.CoinSelection.Consolidation.MaxFeeRate {
+		log.Printf("Wallet holds %d UTXOs but fee rate %.2f sat/vB is above the consolidation limit, waiting",
+			len(utxos), feeRate)
+		return
+	}
+
+	txHash, err := consolidate(utxos, cfg.CoinSelection.Consolidation.MaxInputs, feeRate)
+	if err != nil {
+		utils.LogBlockchainError("UTXOConsolidation", "Failed to consolidate UTXOs", err, map[string]interface{}{
+			"utxos":    len(utxos),
+			"fee_rate": feeRate,
+		})
+		return
+	}
+	log.Printf("Consolidated wallet UTXOs in %s", txHash)
+}
+
+// consolidate spends up to maxInputs of the smallest UTXOs that are worth
+// more than their spending cost into one output
+func consolidate(utxos []utxo, maxInputs int, feeRate float64) (string, error) {
+	sort.Slice(utxos, func(i, j int) bool { return utxos[i].Amount < utxos[j].Amount })
+
+	inputs := []map[string]interface{}{}
+	total := int64(0)
+	vsize := txOverheadVsize
+	for _, u := range utxos {
+		if len(inputs) == maxInputs {
+			break
+		}
+		if btcToSats(u.Amount) <= inputFee(u, feeRate) {
+			continue // dust at this fee rate, leave it for a cheaper time
+		}
+		inputs = append(inputs, map[string]interface{}{"txid": u.Txid, "vout": u.Vout})
+		total += btcToSats(u.Amount)
+		vsize += EstimateInputVsize(u)
+	}
+	if len(inputs) < 2 {
+		return "", fmt.Errorf("fewer than two UTXOs are worth consolidating at %.2f sat/vB", feeRate)
+	}
+
+	fee := FeeForVsize(int64(math.Ceil(vsize))+changeOutputVsize, feeRate)
+	value := total - fee
+	if value < dustLimitSats {
+		return "", fmt.Errorf("consolidated value %d sats is below dust", value)
+	}
+
+	if !UnlockWallet() {
+		return "", fmt.Errorf("failed to unlock wallet")
+	}
+
+	address := GetRawAddress()
+	if address == "" {
+		return "", fmt.Errorf("failed to get consolidation address")
+	}
+
+	log.Printf("Consolidating %d UTXOs (%d sats) into %s, fee %d sats", len(inputs), total, address, fee)
+
+	rawTx, err := callRPC("consolidate_create", "createrawtransaction", []interface{}{
+		inputs,
+		map[string]interface{}{address: satsToBTC(value)},
+		0,
+		true,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	signed := SignRawTransaction(rawTx)
+	if signed == "" {
+		return "", fmt.Errorf("failed to sign consolidation transaction")
+	}
+	var sgn signedtx
+	if err := json.Unmarshal([]byte(signed), &sgn); err != nil {
+		return "", fmt.Errorf("failed to unmarshal signed consolidation transaction: %w", err)
+	}
+
+	txHash := SendSignedTransaction(sgn.Hex)
+	if txHash == "" {
+		return "", fmt.Errorf("failed to broadcast consolidation transaction")
+	}
+	return txHash, nil
+}
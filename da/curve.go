@@ -0,0 +1,308 @@
+package da
+
+import (
+	"crypto/rand"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/crypto/curve25519"
+	"gopkg.in/zeromq/goczmq.v4"
+)
+
+// CURVE keys are 32 byte Curve25519 keys, written as 40 characters of Z85
+// like zmq_curve_keypair and zcert do
+const curveKeySize = 32
+
+// How long the socket loop waits for a message before checking for Destroy
+const curvePollMillis = 250
+
+const z85Alphabet = "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ.-:+=^!/*?&<>()[]{}@%$#"
+
+// Z85Encode encodes data, whose length must be a multiple of 4
+func Z85Encode(data []byte) (string, error) {
+	if len(data)%4 != 0 {
+		return "", fmt.Errorf("z85 input has %d bytes, expected a multiple of 4", len(data))
+	}
+
+	var sb strings.Builder
+	for i := 0; i < len(data); i += 4 {
+		value := uint32(data[i])<<24 | uint32(data[i+1])<<16 | uint32(data[i+2])<<8 | uint32(data[i+3])
+		chunk := make([]byte, 5)
+		for j := 4; j >= 0; j-- {
+			chunk[j] = z85Alphabet[value%85]
+			value /= 85
+		}
+		sb.Write(chunk)
+	}
+	return sb.String(), nil
+}
+
+// Z85Decode decodes text, whose length must be a multiple of 5
+func Z85Decode(text string) ([]byte, error) {
+	if len(text)%5 != 0 {
+		return nil, fmt.Errorf("z85 input has %d characters, expected a multiple of 5", len(text))
+	}
+
+	data := make([]byte, 0, len(text)/5*4)
+	for i := 0; i < len(text); i += 5 {
+		var value uint64
+		for j := 0; j < 5; j++ {
+			digit := strings.IndexByte(z85Alphabet, text[i+j])
+			if digit < 0 {
+				return nil, fmt.Errorf("invalid z85 character %q", text[i+j])
+			}
+			value = value*85 + uint64(digit)
+		}
+		if value > 0xffffffff {
+			return nil, fmt.Errorf("invalid z85 block %q", text[i:i+5])
+		}
+		data = append(data, byte(value>>24), byte(value>>16), byte(value>>8), byte(value))
+	}
+	return data, nil
+}
+
+// GenerateCurveKeyPair returns a new CURVE key pair in Z85
+func GenerateCurveKeyPair() (publicKey string, secretKey string, err error) {
+	secret := make([]byte, curveKeySize)
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", err
+	}
+	secretKey, err = Z85Encode(secret)
+	if err != nil {
+		return "", "", err
+	}
+	publicKey, err = CurvePublicKey(secretKey)
+	return publicKey, secretKey, err
+}
+
+// CurvePublicKey derives the Z85 public key of a Z85 secret key
+func CurvePublicKey(secretKey string) (string, error) {
+	secret, err := decodeCurveKey(secretKey)
+	if err != nil {
+		return "", fmt.Errorf("invalid CURVE secret key: %w", err)
+	}
+	public, err := curve25519.X25519(secret, curve25519.Basepoint)
+	if err != nil {
+		return "", err
+	}
+	return Z85Encode(public)
+}
+
+func decodeCurveKey(key string) ([]byte, error) {
+	data, err := Z85Decode(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) != curveKeySize {
+		return nil, fmt.Errorf("key has %d bytes, expected %d", len(data), curveKeySize)
+	}
+	return data, nil
+}
+
+// CurveChanneler serializes access to a CURVE secured REP or REQ socket
+// through a send and receive channel, like goczmq.Channeler does for plain
+// sockets. goczmq.Channeler creates its socket internally, so it cannot
+// carry the CURVE options.
+type CurveChanneler struct {
+	SendChan chan<- [][]byte
+	RecvChan <-chan [][]byte
+
+	done    chan struct{}
+	stopped chan struct{}
+	auth    *goczmq.Auth
+	certDir string
+}
+
+// NewCurveRepChanneler binds a CURVE server REP socket on endpoint.
+// Connections are only accepted from clientPublicKeys; an empty list accepts
+// any client key, so traffic is encrypted but clients are not authenticated.
+func NewCurveRepChanneler(endpoint string, serverSecretKey string, clientPublicKeys []string) (*CurveChanneler, error) {
+	if _, err := CurvePublicKey(serverSecretKey); err != nil {
+		return nil, err
+	}
+
+	c := &CurveChanneler{}
+	allowed := goczmq.CurveAllowAny
+	if len(clientPublicKeys) > 0 {
+		certDir, err := writeClientCerts(clientPublicKeys)
+		if err != nil {
+			return nil, err
+		}
+		c.certDir = certDir
+		allowed = certDir
+	}
+
+	// zauth answers the ZAP requests of every socket with a zap domain
+	c.auth = goczmq.NewAuth()
+	if err := c.auth.Curve(allowed); err != nil {
+		c.cleanup()
+		return nil, fmt.Errorf("failed to configure CURVE authentication: %w", err)
+	}
+
+	sock := goczmq.NewSock(goczmq.Rep)
+	sock.SetZapDomain("global")
+	sock.SetCurveServer(1)
+	sock.SetCurveSecretkey(serverSecretKey)
+	if err := sock.Attach(endpoint, true); err != nil {
+		sock.Destroy()
+		c.cleanup()
+		return nil, fmt.Errorf("failed to bind %s: %w", endpoint, err)
+	}
+
+	c.start(sock, false)
+	return c, nil
+}
+
+// NewCurveReqChanneler connects a CURVE client REQ socket to a data socket
+// served with serverPublicKey, authenticating with clientSecretKey
+func NewCurveReqChanneler(endpoint string, serverPublicKey string, clientSecretKey string) (*CurveChanneler, error) {
+	if _, err := decodeCurveKey(serverPublicKey); err != nil {
+		return nil, fmt.Errorf("invalid CURVE server key: %w", err)
+	}
+	clientPublicKey, err := CurvePublicKey(clientSecretKey)
+	if err != nil {
+		return nil, err
+	}
+
+	sock := goczmq.NewSock(goczmq.Req)
+	sock.SetCurveServerkey(serverPublicKey)
+	sock.SetCurvePublickey(clientPublicKey)
+	sock.SetCurveSecretkey(clientSecretKey)
+	if err := sock.Attach(endpoint, false); err != nil {
+		sock.Destroy()
+		return nil, fmt.Errorf("failed to connect to %s: %w", endpoint, err)
+	}
+
+	c := &CurveChanneler{}
+	c.start(sock, true)
+	return c, nil
+}
+
+// Destroy stops the socket loop and closes the socket
+func (c *CurveChanneler) Destroy() {
+	close(c.done)
+	<-c.stopped
+	c.cleanup()
+}
+
+func (c *CurveChanneler) cleanup() {
+	if c.auth != nil {
+		c.auth.Destroy()
+		c.auth = nil
+	}
+	if c.certDir != "" {
+		if err := os.RemoveAll(c.certDir); err != nil {
+			log.Printf("Failed to remove CURVE certificate directory %s: %v", c.certDir, err)
+		}
+		c.certDir = ""
+	}
+}
+
+func (c *CurveChanneler) start(sock *goczmq.Sock, sendFirst bool) {
+	sendChan := make(chan [][]byte)
+	recvChan := make(chan [][]byte)
+	c.SendChan = sendChan
+	c.RecvChan = recvChan
+	c.done = make(chan struct{})
+	c.stopped = make(chan struct{})
+
+	go c.run(sock, sendFirst, sendChan, recvChan)
+}
+
+// run owns the socket. REQ and REP sockets strictly alternate between
+// sending and receiving, so the loop does the same instead of polling
+// both directions.
+func (c *CurveChanneler) run(sock *goczmq.Sock, sendFirst bool, sendChan <-chan [][]byte, recvChan chan<- [][]byte) {
+	defer close(c.stopped)
+	defer close(recvChan)
+	defer sock.Destroy()
+
+	poller, err := goczmq.NewPoller(sock)
+	if err != nil {
+		log.Printf("Failed to create CURVE socket poller: %v", err)
+		return
+	}
+	defer poller.Destroy()
+
+	send := func() bool {
+		select {
+		case msg := <-sendChan:
+			if err := sock.SendMessage(msg); err != nil {
+				log.Printf("Failed to send on CURVE socket: %v", err)
+				return false
+			}
+			return true
+		case <-c.done:
+			return false
+		}
+	}
+
+	recv := func() bool {
+		for {
+			select {
+			case <-c.done:
+				return false
+			default:
+			}
+			if poller.Wait(curvePollMillis) == nil {
+				continue
+			}
+
+			msg, err := sock.RecvMessage()
+			if err != nil {
+				log.Printf("Failed to receive on CURVE socket: %v", err)
+				return false
+			}
+			select {
+			case recvChan <- msg:
+				return true
+			case <-c.done:
+				return false
+			}
+		}
+	}
+
+	for {
+		if sendFirst {
+			if !send() || !recv() {
+				return
+			}
+		} else if !recv() || !send() {
+			return
+		}
+	}
+}
+
+// writeClientCerts writes the allowed client keys as public certificates
+// for zauth, which loads its CURVE allowlist from a directory
+func writeClientCerts(clientPublicKeys []string) (string, error) {
+	dir, err := os.MkdirTemp("", "data-block-curve-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create CURVE certificate directory: %w", err)
+	}
+
+	for i, key := range clientPublicKeys {
+		public, err := decodeCurveKey(key)
+		if err != nil {
+			os.RemoveAll(dir)
+			return "", fmt.Errorf("invalid CURVE client key %q: %w", key, err)
+		}
+
+		// zauth only reads the public key of the certificate
+		cert, err := goczmq.NewCertFromKeys(public, make([]byte, curveKeySize))
+		if err != nil {
+			os.RemoveAll(dir)
+			return "", fmt.Errorf("invalid CURVE client key %q: %w", key, err)
+		}
+		err = cert.SavePublic(filepath.Join(dir, fmt.Sprintf("client-%d.cert", i)))
+		cert.Destroy()
+		if err != nil {
+			os.RemoveAll(dir)
+			return "", fmt.Errorf("failed to save CURVE client key %q: %w", key, err)
+		}
+	}
+	return dir, nil
+}
@@ -0,0 +1,227 @@
+package da
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"github.com/Layer-Edge/bitcoin-da/config"
+	"github.com/Layer-Edge/bitcoin-da/models"
+	"github.com/Layer-Edge/bitcoin-da/utils"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+const (
+	// cpfpChildVsize is the size of a one-input (P2WPKH) one-output child
+	cpfpChildVsize = 110
+	// dustLimitSats is the smallest output bitcoind relays for P2WPKH
+	dustLimitSats = 546
+)
+
+// FeeBumperJob watches the mempool for anchors that have been unconfirmed
+// for longer than fee-bumper.stuck-after-minutes and raises their fee rate,
+// first by BIP125 replacement (bumpfee) and otherwise by spending the change
+// output in a higher fee child (CPFP), within the configured budget
+func FeeBumperJob(cfg *config.Config) {
+	err := models.InitDB(cfg.PostgresConnectionURI)
+	if err != nil {
+		log.Fatalf("Error initializing DB Connection: %v", err)
+	}
+	defer func() {
+		if err := models.CloseDB(); err != nil {
+			log.Printf("Error closing database: %v", err)
+		}
+	}()
+
+	InitOPReturnRPC(cfg.BtcEndpoint, cfg.Auth, cfg.WalletPassphrase)
+	InitFeeEstimation(cfg)
+
+	interval := time.Duration(cfg.FeeBumper.PollIntervalSeconds) * time.Second
+	log.Printf("Starting fee bumper (stuck after %d min, max %.1f sat/vB, budget %d sats)",
+		cfg.FeeBumper.StuckAfterMinutes, cfg.FeeBumper.MaxFeeRate, cfg.FeeBumper.MaxFeeSats)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		bumpStuckAnchors(cfg)
+		<-ticker.C
+	}
+}
+
+func bumpStuckAnchors(cfg *config.Config) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Panic in bumpStuckAnchors: %v", r)
+		}
+	}()
+
+	proofs, err := models.GetUnconfirmedSuperProofs()
+	if err != nil {
+		utils.LogDatabaseError("FeeBumper", "Failed to fetch unconfirmed super proofs", err, nil)
+		return
+	}
+
+	for i := range proofs {
+		if proofs[i].BTCBlockHash != nil {
+			continue // already mined, waiting for more confirmations
+		}
+		bumpAnchor(cfg, &proofs[i])
+	}
+}
+
+func bumpAnchor(cfg *config.Config, proof *models.AggregatedProof) {
+	txHash := *proof.BTCTxHash
+
+	entry, err := GetMempoolEntry(txHash)
+	if err != nil {
+		// Not in the mempool: mined or dropped, both handled by the confirmation tracker
+		return
+	}
+
+	age := time.Since(time.Unix(entry.Time, 0))
+	if age < time.Duration(cfg.FeeBumper.StuckAfterMinutes)*time.Minute {
+		return
+	}
+
+	parentFee := btcToSats(entry.Fees.Base)
+	currentRate := float64(parentFee) / float64(entry.Vsize)
+	if entry.DescendantCount > 1 && entry.DescendantSize > 0 {
+		// A CPFP child is already attached, judge the package instead
+		currentRate = float64(btcToSats(entry.Fees.Descendant)) / float64(entry.DescendantSize)
+	}
+
+	// Bump by at least the multiplier, or up to the current network estimate if higher
+	targetRate := math.Max(currentRate*cfg.FeeBumper.BumpMultiplier, currentRate+1)
+	targetRate = math.Max(targetRate, CurrentFeeRate())
+	targetRate = math.Min(targetRate, cfg.FeeBumper.MaxFeeRate)
+	targetRate = math.Min(targetRate, float64(cfg.FeeBumper.MaxFeeSats)/float64(entry.Vsize))
+	targetRate = math.Floor(targetRate*1000) / 1000
+
+	if targetRate <= currentRate {
+		utils.LogBlockchainError("FeeBumper", "Anchor is stuck but the fee budget is exhausted",
+			fmt.Errorf("current rate %.2f sat/vB already at cap", currentRate), map[string]interface{}{
+				"super_proof": proof.ID,
+				"btc_tx_hash": txHash,
+			})
+		return
+	}
+
+	log.Printf("Anchor %s stuck for %v at %.2f sat/vB, bumping to %.2f sat/vB", txHash, age.Round(time.Minute), currentRate, targetRate)
+
+	if !UnlockWallet() {
+		log.Printf("Failed to unlock wallet for fee bump")
+		return
+	}
+
+	bump, err := BumpFee(txHash, targetRate)
+	if err == nil {
+		log.Printf("Replaced anchor %s with %s (fee %.8f -> %.8f BTC)", txHash, bump.Txid, bump.OrigFee, bump.Fee)
+		if err := models.UpdateSuperProofWithBTCTxHash(proof.ID, &bump.Txid, nil); err != nil {
+			utils.LogDatabaseError("FeeBumper", "Failed to record replacement anchor", err, map[string]interface{}{
+				"super_proof": proof.ID,
+				"old_tx_hash": txHash,
+				"new_tx_hash": bump.Txid,
+			})
+		}
+		return
+	}
+
+	log.Printf("bumpfee failed for %s, falling back to CPFP: %v", txHash, err)
+	childTxHash, err := cpfpAnchor(cfg, txHash, entry, parentFee, targetRate)
+	if err != nil {
+		utils.LogBlockchainError("FeeBumper", "Failed to CPFP stuck anchor", err, map[string]interface{}{
+			"super_proof": proof.ID,
+			"btc_tx_hash": txHash,
+		})
+		return
+	}
+
+	log.Printf("Attached CPFP child %s to anchor %s", childTxHash, txHash)
+}
+
+// cpfpAnchor spends the anchor's change output in a child paying enough
+// that the parent and child together reach targetRate
+func cpfpAnchor(cfg *config.Config, txHash string, entry *MempoolEntry, parentFee int64, targetRate float64) (string, error) {
+	walletTx, err := GetWalletTransaction(txHash)
+	if err != nil {
+		return "", err
+	}
+
+	raw, err := hex.DecodeString(walletTx.Hex)
+	if err != nil {
+		return "", fmt.Errorf("invalid transaction hex: %w", err)
+	}
+	var parent wire.MsgTx
+	if err := parent.Deserialize(bytes.NewReader(raw)); err != nil {
+		return "", fmt.Errorf("failed to deserialize anchor: %w", err)
+	}
+
+	changeIndex := -1
+	for i, out := range parent.TxOut {
+		if txscript.GetScriptClass(out.PkScript) != txscript.NullDataTy && out.Value > 0 {
+			changeIndex = i
+			break
+		}
+	}
+	if changeIndex < 0 {
+		return "", fmt.Errorf("anchor %s has no change output to spend", txHash)
+	}
+
+	packageFee := int64(math.Ceil(targetRate * float64(entry.Vsize+cpfpChildVsize)))
+	if packageFee > cfg.FeeBumper.MaxFeeSats {
+		packageFee = cfg.FeeBumper.MaxFeeSats
+	}
+	childFee := packageFee - parentFee
+	if childFee <= 0 {
+		return "", fmt.Errorf("fee budget of %d sats leaves nothing for a child", cfg.FeeBumper.MaxFeeSats)
+	}
+
+	childValue := parent.TxOut[changeIndex].Value - childFee
+	if childValue < dustLimitSats {
+		return "", fmt.Errorf("change output %d sats cannot cover child fee %d sats", parent.TxOut[changeIndex].Value, childFee)
+	}
+
+	address := GetRawAddress()
+	if address == "" {
+		return "", fmt.Errorf("failed to get change address for CPFP child")
+	}
+
+	rawChild, err := callRPC("cpfp_create", "createrawtransaction", []interface{}{
+		[]map[string]interface{}{{"txid": txHash, "vout": changeIndex}},
+		map[string]interface{}{address: satsToBTC(childValue)},
+		0,
+		true,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	signed := SignRawTransaction(rawChild)
+	if signed == "" {
+		return "", fmt.Errorf("failed to sign CPFP child")
+	}
+	var sgn signedtx
+	if err := json.Unmarshal([]byte(signed), &sgn); err != nil {
+		return "", fmt.Errorf("failed to unmarshal signed CPFP child: %w", err)
+	}
+
+	childTxHash := SendSignedTransaction(sgn.Hex)
+	if childTxHash == "" {
+		return "", fmt.Errorf("failed to broadcast CPFP child")
+	}
+	return childTxHash, nil
+}
+
+func btcToSats(amount float64) int64 {
+	return int64(math.Round(amount * 1e8))
+}
+
+func satsToBTC(sats int64) float64 {
+	return float64(sats) / 1e8
+}
@@ -0,0 +1,142 @@
+package da
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+
+	"github.com/Layer-Edge/bitcoin-da/config"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// Fee estimation settings, see `fee-estimation` in the config
+var (
+	feeConfTarget     = 6
+	feeEstimateMode   = "economical"
+	minFeeRate        = 1.0 // sat/vB
+	maxFeeRate        = 100.0
+	fallbackFeeRate   = 5.0
+	changeOutputVsize = int64(31) // P2WPKH, the default getrawchangeaddress type
+	txOverheadVsize   = 11.0      // version, locktime, counts and segwit marker
+	defaultInputVsize = 148.0     // P2PKH, the most expensive common input
+	feeRateTolerance  = 0.98
+)
+
+type smartFeeEstimate struct {
+	FeeRate *float64 `json:"feerate"` // BTC/kvB
+	Errors  []string `json:"errors"`
+	Blocks  int      `json:"blocks"`
+}
+
+// InitFeeEstimation loads the fee estimation settings from the config
+func InitFeeEstimation(cfg *config.Config) {
+	feeConfTarget = cfg.FeeEstimation.ConfTarget
+	feeEstimateMode = cfg.FeeEstimation.Mode
+	minFeeRate = cfg.FeeEstimation.MinFeeRate
+	maxFeeRate = cfg.FeeEstimation.MaxFeeRate
+	fallbackFeeRate = cfg.FeeEstimation.FallbackFeeRate
+}
+
+// EstimateSmartFee returns the node's fee rate estimate in sat/vB for
+// confirmation within confTarget blocks
+func EstimateSmartFee(confTarget int, mode string) (float64, error) {
+	result, err := callRPC("estimate_smart_fee", "estimatesmartfee", []interface{}{confTarget, strings.ToUpper(mode)})
+	if err != nil {
+		return 0, err
+	}
+
+	var estimate smartFeeEstimate
+	if err := json.Unmarshal([]byte(result), &estimate); err != nil {
+		return 0, fmt.Errorf("failed to unmarshal estimatesmartfee result: %w", err)
+	}
+	if estimate.FeeRate == nil {
+		return 0, fmt.Errorf("no fee estimate available: %s", strings.Join(estimate.Errors, "; "))
+	}
+
+	// BTC/kvB -> sat/vB
+	return *estimate.FeeRate * 1e8 / 1000, nil
+}
+
+// CurrentFeeRate returns the estimated fee rate in sat/vB clamped to the
+// configured bounds, or the fallback rate if the node cannot estimate
+func CurrentFeeRate() float64 {
+	rate, err := EstimateSmartFee(feeConfTarget, feeEstimateMode)
+	if err != nil {
+		log.Printf("Fee estimation unavailable, using fallback %.2f sat/vB: %v", fallbackFeeRate, err)
+		rate = fallbackFeeRate
+	}
+
+	rate = math.Max(rate, minFeeRate)
+	rate = math.Min(rate, maxFeeRate)
+	log.Printf("Using fee rate %.2f sat/vB (target %d blocks, %s)", rate, feeConfTarget, feeEstimateMode)
+	return rate
+}
+
+// EstimateInputVsize returns the expected signed size of spending u, based
+// on its scriptPubKey type
+func EstimateInputVsize(u utxo) float64 {
+	script, err := hex.DecodeString(u.ScriptPubKey)
+	if err != nil {
+		return defaultInputVsize
+	}
+
+	switch txscript.GetScriptClass(script) {
+	case txscript.WitnessV0PubKeyHashTy:
+		return 68
+	case txscript.WitnessV1TaprootTy:
+		return 57.5
+	case txscript.ScriptHashTy:
+		return 91 // assume P2SH-wrapped P2WPKH
+	default:
+		return defaultInputVsize
+	}
+}
+
+// opReturnOutputVsize returns the size of an OP_RETURN output carrying dataSize bytes
+func opReturnOutputVsize(dataSize int) int64 {
+	script := 1 + dataSize // OP_RETURN + data
+	switch {
+	case dataSize <= txscript.OP_DATA_75:
+		script++
+	case dataSize <= math.MaxUint8:
+		script += 2
+	default:
+		script += 3
+	}
+	return int64(8 + wire.VarIntSerializeSize(uint64(script)) + script)
+}
+
+// EstimateTxVsize estimates the vsize of an anchor spending inputs with one
+// OP_RETURN output of dataSize bytes and one change output
+func EstimateTxVsize(inputs []utxo, dataSize int) int64 {
+	vsize := txOverheadVsize
+	for _, u := range inputs {
+		vsize += EstimateInputVsize(u)
+	}
+	return int64(math.Ceil(vsize)) + opReturnOutputVsize(dataSize) + changeOutputVsize
+}
+
+// TxVsize returns the virtual size of a serialized (signed) transaction
+func TxVsize(rawHex string) (int64, error) {
+	raw, err := hex.DecodeString(rawHex)
+	if err != nil {
+		return 0, fmt.Errorf("invalid transaction hex: %w", err)
+	}
+
+	var tx wire.MsgTx
+	if err := tx.Deserialize(bytes.NewReader(raw)); err != nil {
+		return 0, fmt.Errorf("failed to deserialize transaction: %w", err)
+	}
+
+	return msgTxVsize(&tx), nil
+}
+
+// FeeForVsize returns the fee in satoshis for vsize at feeRate sat/vB
+func FeeForVsize(vsize int64, feeRate float64) int64 {
+	return int64(math.Ceil(float64(vsize) * feeRate))
+}
@@ -0,0 +1,76 @@
+package da
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Layer-Edge/bitcoin-da/config"
+	"github.com/Layer-Edge/bitcoin-da/utils"
+	"github.com/consensys/gnark-crypto/ecc"
+)
+
+// Verification outcomes stored with a pending proof. Proofs received while
+// the verification stage is disabled have an empty verification.
+const (
+	VerificationVerified   = "verified"
+	VerificationUnverified = "unverified" // no verifying key for the namespace
+)
+
+// Groth16Verifier verifies proofs with the BN254 Groth16 verifying key of
+// their namespace. A proof is the gnark proof followed by its public witness.
+type Groth16Verifier struct {
+	keys            map[string]*namespaceVerifyingKey
+	allowUnverified bool
+}
+
+type namespaceVerifyingKey struct {
+	validator *GnarkValidator
+	hash      string // keccak256 of the verifying key file
+}
+
+// NewGroth16Verifier loads the verifying key of every configured namespace
+func NewGroth16Verifier(cfg *config.Config) (*Groth16Verifier, error) {
+	v := &Groth16Verifier{
+		keys:            map[string]*namespaceVerifyingKey{},
+		allowUnverified: cfg.Groth16Verification.AllowUnverified,
+	}
+
+	for _, key := range cfg.Groth16Verification.Keys {
+		if err := ValidateNamespace(key.Namespace); err != nil {
+			return nil, err
+		}
+		if _, ok := v.keys[key.Namespace]; ok {
+			return nil, fmt.Errorf("namespace %q has more than one verifying key", key.Namespace)
+		}
+
+		data, err := os.ReadFile(key.VerifyingKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read verifying key of %s: %w", key.Namespace, err)
+		}
+		validator, err := parseGnarkValidator("groth16", ecc.BN254, data)
+		if err != nil {
+			return nil, fmt.Errorf("verifying key %s of %s: %w", key.VerifyingKey, key.Namespace, err)
+		}
+		v.keys[key.Namespace] = &namespaceVerifyingKey{validator: validator, hash: utils.Keccak256Hash(data)}
+	}
+	return v, nil
+}
+
+// Verify checks proof against the verifying key of namespace and returns the
+// verification to store with it and the hash of the key used. Proofs that
+// fail, and proofs of namespaces without a key unless unverified proofs are
+// allowed, are returned as an error.
+func (v *Groth16Verifier) Verify(namespace string, proof []byte) (string, string, error) {
+	key, ok := v.keys[namespace]
+	if !ok {
+		if !v.allowUnverified {
+			return "", "", fmt.Errorf("namespace %q has no verifying key", namespace)
+		}
+		return VerificationUnverified, "", nil
+	}
+
+	if err := key.validator.Validate(proof); err != nil {
+		return "", "", err
+	}
+	return VerificationVerified, key.hash, nil
+}
@@ -0,0 +1,300 @@
+package da
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net"
+	"time"
+
+	"github.com/Layer-Edge/bitcoin-da/config"
+	aggregationv1 "github.com/Layer-Edge/bitcoin-da/proto/aggregation/v1"
+	"github.com/Layer-Edge/bitcoin-da/utils"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var proofStages = map[string]aggregationv1.ProofStage{
+	ProofStageUnknown:      aggregationv1.ProofStage_PROOF_STAGE_UNKNOWN,
+	ProofStagePending:      aggregationv1.ProofStage_PROOF_STAGE_PENDING,
+	ProofStageAggregated:   aggregationv1.ProofStage_PROOF_STAGE_AGGREGATED,
+	ProofStageStored:       aggregationv1.ProofStage_PROOF_STAGE_STORED,
+	ProofStageInSuperProof: aggregationv1.ProofStage_PROOF_STAGE_IN_SUPER_PROOF,
+	ProofStageAnchored:     aggregationv1.ProofStage_PROOF_STAGE_ANCHORED,
+	ProofStageConfirmed:    aggregationv1.ProofStage_PROOF_STAGE_CONFIRMED,
+	ProofStageRejected:     aggregationv1.ProofStage_PROOF_STAGE_REJECTED,
+}
+
+var ackStatuses = map[AckStatus]aggregationv1.AckStatus{
+	AckStatusAccepted:  aggregationv1.AckStatus_ACK_STATUS_ACCEPTED,
+	AckStatusDuplicate: aggregationv1.AckStatus_ACK_STATUS_DUPLICATE,
+	AckStatusRejected:  aggregationv1.AckStatus_ACK_STATUS_REJECTED,
+	AckStatusFailed:    aggregationv1.AckStatus_ACK_STATUS_FAILED,
+	AckStatusThrottled: aggregationv1.AckStatus_ACK_STATUS_THROTTLED,
+	AckStatusUnknown:   aggregationv1.AckStatus_ACK_STATUS_UNKNOWN,
+}
+
+// AggregationService implements the gRPC AggregationService on top of the
+// HashBlockSubscriber pipeline and the models package
+type AggregationService struct {
+	aggregationv1.UnimplementedAggregationServiceServer
+
+	protocolId    string
+	submitTimeout time.Duration
+	watchInterval time.Duration
+
+	// Lookups, replaceable so the service can run against fixtures
+	proofStatus  func(leaf string) (*ProofStatus, error)
+	verifyAnchor func(protocolId string, p *AnchoredInclusionProof) (*AnchorVerification, error)
+}
+
+// NewAggregationService creates the service with the grpc settings from the config
+func NewAggregationService(cfg *config.Config) *AggregationService {
+	return &AggregationService{
+		protocolId:    cfg.ProtocolId,
+		submitTimeout: time.Duration(cfg.GRPC.SubmitTimeoutSeconds) * time.Second,
+		watchInterval: time.Duration(cfg.GRPC.WatchPollIntervalSeconds) * time.Second,
+		proofStatus:   GetProofStatus,
+		verifyAnchor:  VerifyAnchor,
+	}
+}
+
+// NewGRPCServer returns a server with the AggregationService registered. It
+// is not listening yet, so tests can serve it on a bufconn listener.
+func NewGRPCServer(svc *AggregationService, opts ...grpc.ServerOption) *grpc.Server {
+	server := grpc.NewServer(opts...)
+	aggregationv1.RegisterAggregationServiceServer(server, svc)
+	return server
+}
+
+// GRPCServer serves the AggregationService on grpc.listen-address.
+// Submissions go through the same pipeline as the data socket, so
+// HashBlockSubscriber must be running in the same process; queries use the
+// database connection it opens.
+func GRPCServer(cfg *config.Config) {
+	InitOPReturnRPC(cfg.BtcEndpoint, cfg.Auth, cfg.WalletPassphrase)
+
+	lis, err := net.Listen("tcp", cfg.GRPC.ListenAddress)
+	if err != nil {
+		log.Fatalf("Failed to listen on %s: %v", cfg.GRPC.ListenAddress, err)
+	}
+
+	log.Printf("Starting gRPC server on %s", cfg.GRPC.ListenAddress)
+	if err := NewGRPCServer(NewAggregationService(cfg)).Serve(lis); err != nil {
+		log.Fatalf("gRPC server stopped: %v", err)
+	}
+}
+
+func (s *AggregationService) SubmitProof(ctx context.Context, req *aggregationv1.SubmitProofRequest) (*aggregationv1.SubmitProofResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.submitTimeout)
+	defer cancel()
+
+	return &aggregationv1.SubmitProofResponse{Ack: s.submit(ctx, req)}, nil
+}
+
+func (s *AggregationService) SubmitBatch(ctx context.Context, req *aggregationv1.SubmitBatchRequest) (*aggregationv1.SubmitBatchResponse, error) {
+	if len(req.Proofs) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "batch is empty")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.submitTimeout)
+	defer cancel()
+
+	resp := &aggregationv1.SubmitBatchResponse{Acks: make([]*aggregationv1.Ack, 0, len(req.Proofs))}
+	for _, proof := range req.Proofs {
+		resp.Acks = append(resp.Acks, s.submit(ctx, proof))
+	}
+	return resp, nil
+}
+
+func (s *AggregationService) WatchProof(req *aggregationv1.WatchProofRequest, stream aggregationv1.AggregationService_WatchProofServer) error {
+	ticker := time.NewTicker(s.watchInterval)
+	defer ticker.Stop()
+
+	lastStage := ""
+	for {
+		proofStatus, err := s.proofStatus(req.ProofId)
+		if err != nil {
+			return proofStatusError(req.ProofId, err)
+		}
+		if proofStatus.Stage == ProofStageUnknown {
+			return status.Errorf(codes.NotFound, "proof %s was not submitted", proofStatus.ProofID)
+		}
+
+		if proofStatus.Stage != lastStage {
+			if err := stream.Send(toProtoProofStatus(proofStatus)); err != nil {
+				return err
+			}
+			lastStage = proofStatus.Stage
+		}
+		if proofStatus.Stage == ProofStageConfirmed || proofStatus.Stage == ProofStageRejected {
+			return nil
+		}
+
+		select {
+		case <-ticker.C:
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		}
+	}
+}
+
+func (s *AggregationService) GetInclusionProof(ctx context.Context, req *aggregationv1.GetInclusionProofRequest) (*aggregationv1.GetInclusionProofResponse, error) {
+	proofStatus, err := s.proofStatus(req.ProofId)
+	if err != nil {
+		return nil, proofStatusError(req.ProofId, err)
+	}
+	if proofStatus.Stage == ProofStageUnknown {
+		return nil, status.Errorf(codes.NotFound, "proof %s was not submitted", proofStatus.ProofID)
+	}
+	if proofStatus.InclusionProof == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "proof %s is %s, no inclusion proof yet", proofStatus.ProofID, proofStatus.Stage)
+	}
+
+	return &aggregationv1.GetInclusionProofResponse{Proof: toProtoAnchoredInclusionProof(proofStatus.InclusionProof)}, nil
+}
+
+func (s *AggregationService) VerifyAnchor(ctx context.Context, req *aggregationv1.VerifyAnchorRequest) (*aggregationv1.VerifyAnchorResponse, error) {
+	if req.Proof == nil || req.Proof.Aggregate == nil {
+		return nil, status.Error(codes.InvalidArgument, "proof is required")
+	}
+
+	result, err := s.verifyAnchor(s.protocolId, fromProtoAnchoredInclusionProof(req.Proof))
+	if err != nil {
+		utils.LogBlockchainError("AggregationService", "Failed to verify anchor", err, nil)
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+
+	return &aggregationv1.VerifyAnchorResponse{
+		Valid:            result.Valid,
+		Reason:           result.Reason,
+		BtcTxHash:        result.BTCTxHash,
+		BtcBlockNumber:   result.BTCBlockNumber,
+		BtcConfirmations: result.BTCConfirmations,
+	}, nil
+}
+
+// proofStatusError maps a failed status lookup to InvalidArgument for a bad
+// proof id and to a logged Internal error otherwise
+func proofStatusError(proofID string, err error) error {
+	if errors.Is(err, ErrInvalidProofID) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	log.Printf("Failed to fetch status of proof %s: %v", proofID, err)
+	return status.Error(codes.Internal, "failed to fetch proof status")
+}
+
+// submit hands one proof to HashBlockSubscriber. A writer that does not
+// answer in time yields a failed or unknown ACK, like the HTTP API.
+func (s *AggregationService) submit(ctx context.Context, req *aggregationv1.SubmitProofRequest) *aggregationv1.Ack {
+	ack, err := SubmitProof(ctx, &Submission{
+		Namespace: req.Namespace,
+		Label:     req.Label,
+		ProofType: req.ProofType,
+		Proof:     req.Proof,
+		Signature: req.Signature,
+	})
+	if err != nil {
+		log.Printf("gRPC submission failed: %v", err)
+		ack = SubmissionErrorAck(req.Proof, err)
+	}
+	return toProtoAck(ack)
+}
+
+func toProtoAck(ack *Ack) *aggregationv1.Ack {
+	pb := &aggregationv1.Ack{
+		Status:  ackStatuses[ack.Status], // unmapped statuses stay unspecified
+		ProofId: ack.ProofID,
+		Reason:  ack.Reason,
+
+		RetryAfterSeconds: uint32(ack.RetryAfterSeconds),
+		AggregateRoot:     ack.AggregateRoot,
+	}
+	if ack.Window != nil {
+		pb.Window = &aggregationv1.AckWindow{
+			Start: timestamppb.New(ack.Window.Start),
+			End:   timestamppb.New(ack.Window.End),
+		}
+	}
+	return pb
+}
+
+func toProtoProofStatus(s *ProofStatus) *aggregationv1.ProofStatus {
+	pb := &aggregationv1.ProofStatus{
+		ProofId:          s.ProofID,
+		Stage:            proofStages[s.Stage],
+		Namespace:        s.Namespace,
+		AggregateRoot:    s.AggregateRoot,
+		AggregateTxHash:  s.AggregateTxHash,
+		SuperProofRoot:   s.SuperProofRoot,
+		SuperProofTxHash: s.SuperProofTxHash,
+		BtcTxHash:        s.BTCTxHash,
+		BtcBlockNumber:   s.BTCBlockNumber,
+		BtcConfirmations: s.BTCConfirmations,
+		Error:            s.Error,
+		Verification:     s.Verification,
+	}
+	if s.ReceivedAt != nil {
+		pb.ReceivedAt = timestamppb.New(*s.ReceivedAt)
+	}
+	if s.LeafIndex != nil {
+		index := uint32(*s.LeafIndex)
+		pb.LeafIndex = &index
+	}
+	if s.InclusionProof != nil {
+		pb.InclusionProof = toProtoAnchoredInclusionProof(s.InclusionProof)
+	}
+	return pb
+}
+
+func toProtoInclusionProof(p *InclusionProof) *aggregationv1.InclusionProof {
+	return &aggregationv1.InclusionProof{
+		Leaf:     p.Leaf,
+		Root:     p.Root,
+		Index:    uint32(p.Index),
+		Siblings: p.Siblings,
+	}
+}
+
+func fromProtoInclusionProof(pb *aggregationv1.InclusionProof) *InclusionProof {
+	return &InclusionProof{
+		Leaf:     pb.Leaf,
+		Root:     pb.Root,
+		Index:    int(pb.Index),
+		Siblings: pb.Siblings,
+	}
+}
+
+func toProtoAnchoredInclusionProof(p *AnchoredInclusionProof) *aggregationv1.AnchoredInclusionProof {
+	pb := &aggregationv1.AnchoredInclusionProof{
+		Aggregate:        toProtoInclusionProof(&p.Aggregate),
+		AggregateTxHash:  p.AggregateTxHash,
+		SuperProofTxHash: p.SuperProofTxHash,
+		BtcBlockNumber:   p.BTCBlockNumber,
+	}
+	if p.SuperProof != nil {
+		pb.SuperProof = toProtoInclusionProof(p.SuperProof)
+	}
+	if p.BTCTxHash != nil {
+		pb.BtcTxHash = *p.BTCTxHash
+	}
+	return pb
+}
+
+func fromProtoAnchoredInclusionProof(pb *aggregationv1.AnchoredInclusionProof) *AnchoredInclusionProof {
+	p := &AnchoredInclusionProof{
+		Aggregate:        *fromProtoInclusionProof(pb.Aggregate),
+		AggregateTxHash:  pb.AggregateTxHash,
+		SuperProofTxHash: pb.SuperProofTxHash,
+		BTCBlockNumber:   pb.BtcBlockNumber,
+	}
+	if pb.SuperProof != nil {
+		p.SuperProof = fromProtoInclusionProof(pb.SuperProof)
+	}
+	if pb.BtcTxHash != "" {
+		p.BTCTxHash = &pb.BtcTxHash
+	}
+	return p
+}
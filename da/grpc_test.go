@@ -0,0 +1,243 @@
+package da
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/Layer-Edge/bitcoin-da/merkle"
+	aggregationv1 "github.com/Layer-Edge/bitcoin-da/proto/aggregation/v1"
+	"github.com/Layer-Edge/bitcoin-da/utils"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// fakeWriter answers submissions in place of HashBlockSubscriber with the ACK
+// returned by answer, or takes them without answering when it returns nil
+func fakeWriter(t *testing.T, answer func(sub *Submission) *Ack) {
+	done := make(chan struct{})
+	t.Cleanup(func() { close(done) })
+
+	go func() {
+		for {
+			select {
+			case sub := <-submissions:
+				if ack := answer(sub); ack != nil {
+					sub.reply <- ack
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+}
+
+// answerByLabel replies to each submission with the ACK status named by its label
+func answerByLabel(sub *Submission) *Ack {
+	proofID := utils.Keccak256Hash(sub.Proof)
+	switch sub.Label {
+	case "accepted":
+		ack := NewAck(AckStatusAccepted, proofID, "")
+		ack.Window = &AckWindow{Start: time.Unix(1700000000, 0).UTC(), End: time.Unix(1700000060, 0).UTC()}
+		return ack
+	case "duplicate":
+		ack := NewAck(AckStatusDuplicate, proofID, "proof was already aggregated")
+		ack.AggregateRoot = "0xroot"
+		return ack
+	case "rejected":
+		return NewAck(AckStatusRejected, proofID, "invalid signature")
+	case "failed":
+		return NewAck(AckStatusFailed, proofID, "failed to store proof, please retry")
+	case "throttled":
+		return NewThrottledAck(proofID, "window quota reached", 3*time.Second)
+	case "silent":
+		return nil
+	}
+	return NewAck(AckStatusRejected, proofID, "unexpected label "+sub.Label)
+}
+
+// newTestClient serves svc on a bufconn listener and returns a client for it
+func newTestClient(t *testing.T, svc *AggregationService) aggregationv1.AggregationServiceClient {
+	lis := bufconn.Listen(1 << 20)
+	server := NewGRPCServer(svc)
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("failed to dial bufconn: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return aggregationv1.NewAggregationServiceClient(conn)
+}
+
+func proofRequest(label string, proof string) *aggregationv1.SubmitProofRequest {
+	return &aggregationv1.SubmitProofRequest{Namespace: "test", Label: label, Proof: []byte(proof)}
+}
+
+func TestSubmitProof(t *testing.T) {
+	fakeWriter(t, answerByLabel)
+	client := newTestClient(t, &AggregationService{submitTimeout: time.Second})
+
+	tests := []struct {
+		name       string
+		req        *aggregationv1.SubmitProofRequest
+		status     aggregationv1.AckStatus
+		retryAfter uint32
+		root       string
+		window     bool
+	}{
+		{name: "accepted", req: proofRequest("accepted", "p1"), status: aggregationv1.AckStatus_ACK_STATUS_ACCEPTED, window: true},
+		{name: "duplicate", req: proofRequest("duplicate", "p2"), status: aggregationv1.AckStatus_ACK_STATUS_DUPLICATE, root: "0xroot"},
+		{name: "rejected by the writer", req: proofRequest("rejected", "p3"), status: aggregationv1.AckStatus_ACK_STATUS_REJECTED},
+		{name: "rejected before the writer", req: proofRequest("accepted", ""), status: aggregationv1.AckStatus_ACK_STATUS_REJECTED},
+		{name: "reserved label", req: proofRequest(StatusQueryLabel, "p5"), status: aggregationv1.AckStatus_ACK_STATUS_REJECTED},
+		{name: "throttled", req: proofRequest("throttled", "p4"), status: aggregationv1.AckStatus_ACK_STATUS_THROTTLED, retryAfter: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := client.SubmitProof(context.Background(), tt.req)
+			if err != nil {
+				t.Fatalf("SubmitProof: %v", err)
+			}
+			ack := resp.Ack
+			if ack.Status != tt.status {
+				t.Fatalf("status = %v, want %v (reason %q)", ack.Status, tt.status, ack.Reason)
+			}
+			if len(tt.req.Proof) > 0 && ack.ProofId != utils.Keccak256Hash(tt.req.Proof) {
+				t.Errorf("proof id = %q, want the keccak256 of the proof", ack.ProofId)
+			}
+			if ack.RetryAfterSeconds != tt.retryAfter {
+				t.Errorf("retry after = %d, want %d", ack.RetryAfterSeconds, tt.retryAfter)
+			}
+			if ack.AggregateRoot != tt.root {
+				t.Errorf("aggregate root = %q, want %q", ack.AggregateRoot, tt.root)
+			}
+			if (ack.Window != nil) != tt.window {
+				t.Errorf("window = %v, want set %v", ack.Window, tt.window)
+			}
+		})
+	}
+}
+
+func TestSubmitBatch(t *testing.T) {
+	fakeWriter(t, answerByLabel)
+	client := newTestClient(t, &AggregationService{submitTimeout: 200 * time.Millisecond})
+
+	tests := []struct {
+		name   string
+		proofs []*aggregationv1.SubmitProofRequest
+		want   []aggregationv1.AckStatus
+	}{
+		{
+			name: "every outcome",
+			proofs: []*aggregationv1.SubmitProofRequest{
+				proofRequest("accepted", "b1"),
+				proofRequest("duplicate", "b2"),
+				proofRequest("rejected", "b3"),
+				proofRequest("throttled", "b4"),
+			},
+			want: []aggregationv1.AckStatus{
+				aggregationv1.AckStatus_ACK_STATUS_ACCEPTED,
+				aggregationv1.AckStatus_ACK_STATUS_DUPLICATE,
+				aggregationv1.AckStatus_ACK_STATUS_REJECTED,
+				aggregationv1.AckStatus_ACK_STATUS_THROTTLED,
+			},
+		},
+		{
+			name: "one proof fails partway through",
+			proofs: []*aggregationv1.SubmitProofRequest{
+				proofRequest("accepted", "c1"),
+				proofRequest("failed", "c2"),
+				proofRequest("accepted", "c3"),
+			},
+			want: []aggregationv1.AckStatus{
+				aggregationv1.AckStatus_ACK_STATUS_ACCEPTED,
+				aggregationv1.AckStatus_ACK_STATUS_FAILED,
+				aggregationv1.AckStatus_ACK_STATUS_ACCEPTED,
+			},
+		},
+		{
+			// The batch shares one timeout: the proof the writer took is
+			// unknown and the ones after it are not taken at all
+			name: "writer stops answering partway through",
+			proofs: []*aggregationv1.SubmitProofRequest{
+				proofRequest("accepted", "d1"),
+				proofRequest("silent", "d2"),
+				proofRequest("accepted", "d3"),
+			},
+			want: []aggregationv1.AckStatus{
+				aggregationv1.AckStatus_ACK_STATUS_ACCEPTED,
+				aggregationv1.AckStatus_ACK_STATUS_UNKNOWN,
+				aggregationv1.AckStatus_ACK_STATUS_FAILED,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := client.SubmitBatch(context.Background(), &aggregationv1.SubmitBatchRequest{Proofs: tt.proofs})
+			if err != nil {
+				t.Fatalf("SubmitBatch: %v", err)
+			}
+			if len(resp.Acks) != len(tt.want) {
+				t.Fatalf("got %d ACKs, want %d", len(resp.Acks), len(tt.want))
+			}
+			for i, ack := range resp.Acks {
+				if ack.Status != tt.want[i] {
+					t.Errorf("ACK %d status = %v, want %v (reason %q)", i, ack.Status, tt.want[i], ack.Reason)
+				}
+				if ack.ProofId != utils.Keccak256Hash(tt.proofs[i].Proof) {
+					t.Errorf("ACK %d proof id = %q, want the keccak256 of its proof", i, ack.ProofId)
+				}
+			}
+		})
+	}
+}
+
+func TestSubmitBatchEmpty(t *testing.T) {
+	client := newTestClient(t, &AggregationService{submitTimeout: time.Second})
+
+	if _, err := client.SubmitBatch(context.Background(), &aggregationv1.SubmitBatchRequest{}); err == nil {
+		t.Fatal("empty batch was accepted")
+	}
+}
+
+func TestToProtoAckNeverUnspecified(t *testing.T) {
+	for status := AckStatusAccepted; status <= AckStatusUnknown; status++ {
+		if got := toProtoAck(NewAck(status, "", "")).Status; got == aggregationv1.AckStatus_ACK_STATUS_UNSPECIFIED {
+			t.Errorf("%v maps to ACK_STATUS_UNSPECIFIED", status)
+		}
+	}
+}
+
+func TestGetInclusionProofErrors(t *testing.T) {
+	client := newTestClient(t, &AggregationService{proofStatus: fixtureProofStatus})
+
+	tests := []struct {
+		name string
+		leaf string
+		code codes.Code
+	}{
+		{name: "invalid proof id", leaf: "0x1234", code: codes.InvalidArgument},
+		{name: "unknown proof", leaf: merkle.HashLeaf([]byte("unknown")).Hex(), code: codes.NotFound},
+		{name: "no inclusion proof yet", leaf: merkle.HashLeaf([]byte("stored")).Hex(), code: codes.FailedPrecondition},
+		{name: "lookup fails", leaf: merkle.HashLeaf([]byte("db down")).Hex(), code: codes.Internal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.GetInclusionProof(context.Background(), &aggregationv1.GetInclusionProofRequest{ProofId: tt.leaf})
+			if code := status.Code(err); code != tt.code {
+				t.Errorf("code = %v, want %v (%v)", code, tt.code, err)
+			}
+		})
+	}
+}
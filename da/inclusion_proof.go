@@ -0,0 +1,212 @@
+package da
+
+import (
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Layer-Edge/bitcoin-da/clients"
+	"github.com/Layer-Edge/bitcoin-da/config"
+	"github.com/Layer-Edge/bitcoin-da/merkle"
+	"github.com/Layer-Edge/bitcoin-da/models"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// InclusionProof shows that Leaf sits at Index under Root
+type InclusionProof struct {
+	Leaf     string   `json:"leaf"`
+	Root     string   `json:"root"`
+	Index    int      `json:"index"`
+	Siblings []string `json:"siblings"`
+}
+
+// AnchoredInclusionProof links a submitted proof's leaf to the super proof
+// root that is stored on LayerEdge and anchored on Bitcoin. Aggregate proves
+// leaf -> aggregate root, SuperProof proves aggregate root -> super-proof root.
+type AnchoredInclusionProof struct {
+	Aggregate  InclusionProof  `json:"aggregate"`
+	SuperProof *InclusionProof `json:"super_proof,omitempty"`
+
+	AggregateTxHash  string  `json:"aggregate_tx_hash"`
+	SuperProofTxHash string  `json:"super_proof_tx_hash,omitempty"`
+	BTCTxHash        *string `json:"btc_tx_hash,omitempty"`
+	BTCBlockNumber   *int64  `json:"btc_block_number,omitempty"`
+}
+
+// AggregateLeaves returns the merkle leaves of a stored aggregated proof.
+// Regular aggregates store the hex encoded ABI proofs, whose keccak256 hashes
+// are the leaves; super proofs store the aggregate roots directly.
+func AggregateLeaves(ap *models.AggregatedProof) ([]string, error) {
+	if ap.IsSuperProof() {
+		return ap.Proofs, nil
+	}
+
+	leaves := make([]string, 0, len(ap.Proofs))
+	for _, proof := range ap.Proofs {
+		raw, err := hex.DecodeString(strings.TrimPrefix(proof, "0x"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid stored proof in aggregate %s: %w", ap.ID, err)
+		}
+		leaves = append(leaves, merkle.HashLeaf(raw).Hex())
+	}
+	return leaves, nil
+}
+
+// BuildInclusionProof builds the path from leaf to the root of a stored aggregated proof
+func BuildInclusionProof(ap *models.AggregatedProof, leaf string) (*InclusionProof, error) {
+	leaves, err := AggregateLeaves(ap)
+	if err != nil {
+		return nil, err
+	}
+
+	tree, err := merkle.FromHex(leaves)
+	if err != nil {
+		return nil, err
+	}
+
+	root := ap.MerkleRoot
+	if normalizeRoot(tree.Root().Hex()) != normalizeRoot(root) {
+		return nil, fmt.Errorf("rebuilt root %s does not match stored root %s for aggregate %s", tree.Root().Hex(), root, ap.ID)
+	}
+
+	leafHash, err := merkle.ParseLeaf(leaf)
+	if err != nil {
+		return nil, err
+	}
+
+	index := tree.IndexOf(leafHash)
+	if index < 0 {
+		return nil, fmt.Errorf("leaf %s is not part of aggregate %s", leafHash.Hex(), ap.ID)
+	}
+
+	path, err := tree.Proof(index)
+	if err != nil {
+		return nil, err
+	}
+
+	siblings := make([]string, len(path))
+	for i, s := range path {
+		siblings[i] = s.Hex()
+	}
+
+	return &InclusionProof{
+		Leaf:     leafHash.Hex(),
+		Root:     tree.Root().Hex(),
+		Index:    index,
+		Siblings: siblings,
+	}, nil
+}
+
+// BuildAnchoredInclusionProof builds the two-level proof for leaf inside
+// aggregate. SuperProof is left nil when the aggregate has not yet been
+// rolled into a super proof.
+func BuildAnchoredInclusionProof(aggregate *models.AggregatedProof, leaf string) (*AnchoredInclusionProof, error) {
+	return buildAnchoredInclusionProof(aggregate, leaf, models.GetSuperProofContainingRoot)
+}
+
+// buildAnchoredInclusionProof is BuildAnchoredInclusionProof with the super
+// proof containing a root looked up by superProofOf
+func buildAnchoredInclusionProof(aggregate *models.AggregatedProof, leaf string, superProofOf func(root string) (*models.AggregatedProof, error)) (*AnchoredInclusionProof, error) {
+	leafProof, err := BuildInclusionProof(aggregate, leaf)
+	if err != nil {
+		return nil, err
+	}
+
+	proof := &AnchoredInclusionProof{
+		Aggregate:       *leafProof,
+		AggregateTxHash: aggregate.TransactionHash,
+	}
+
+	superProof, err := superProofOf(aggregate.MerkleRoot)
+	if errors.Is(err, sql.ErrNoRows) {
+		return proof, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rootProof, err := BuildInclusionProof(superProof, aggregate.MerkleRoot)
+	if err != nil {
+		return nil, fmt.Errorf("error building super proof path: %w", err)
+	}
+
+	proof.SuperProof = rootProof
+	proof.SuperProofTxHash = superProof.TransactionHash
+	proof.BTCTxHash = superProof.BTCTxHash
+	proof.BTCBlockNumber = superProof.BTCBlockNumber
+	return proof, nil
+}
+
+// Verify checks the sibling path against the proof's root
+func (p *InclusionProof) Verify() error {
+	leaf, err := merkle.ParseLeaf(p.Leaf)
+	if err != nil {
+		return err
+	}
+	root, err := merkle.ParseLeaf(p.Root)
+	if err != nil {
+		return fmt.Errorf("invalid root: %w", err)
+	}
+	siblings, err := merkle.ParseLeaves(p.Siblings)
+	if err != nil {
+		return fmt.Errorf("invalid sibling: %w", err)
+	}
+
+	if !merkle.Verify(root, leaf, siblings) {
+		return fmt.Errorf("leaf %s does not hash up to root %s", p.Leaf, p.Root)
+	}
+	return nil
+}
+
+// Verify checks both levels of the proof and that they chain into
+// expectedRoot, the super-proof root read from LayerEdge or Bitcoin
+func (p *AnchoredInclusionProof) Verify(expectedRoot string) error {
+	if err := p.Aggregate.Verify(); err != nil {
+		return fmt.Errorf("aggregate level: %w", err)
+	}
+	if p.SuperProof == nil {
+		return fmt.Errorf("aggregate %s is not part of a super proof yet", p.Aggregate.Root)
+	}
+	if err := p.SuperProof.Verify(); err != nil {
+		return fmt.Errorf("super proof level: %w", err)
+	}
+
+	if common.HexToHash(p.SuperProof.Leaf) != common.HexToHash(p.Aggregate.Root) {
+		return fmt.Errorf("super proof leaf %s does not match aggregate root %s", p.SuperProof.Leaf, p.Aggregate.Root)
+	}
+	if normalizeRoot(p.SuperProof.Root) != normalizeRoot(expectedRoot) {
+		return fmt.Errorf("super proof root %s does not match expected root %s", p.SuperProof.Root, expectedRoot)
+	}
+	return nil
+}
+
+// VerifyAnchoredInclusionProofOnChain verifies both levels of the proof and
+// checks that the aggregate and super-proof roots exist on LayerEdge
+func VerifyAnchoredInclusionProofOnChain(cfg *config.Config, p *AnchoredInclusionProof) error {
+	if p.SuperProof == nil {
+		return fmt.Errorf("aggregate %s is not part of a super proof yet", p.Aggregate.Root)
+	}
+	if err := p.Verify(p.SuperProof.Root); err != nil {
+		return err
+	}
+
+	exists, err := clients.TreeExists(cfg, cfg.LayerEdgeRPC.MerkleTreeStorageContract, p.Aggregate.Root)
+	if err != nil {
+		return fmt.Errorf("error checking aggregate root on LayerEdge: %w", err)
+	}
+	if !exists {
+		return fmt.Errorf("aggregate root %s not found in MerkleTreeStorage", p.Aggregate.Root)
+	}
+
+	exists, err = clients.TreeExists(cfg, cfg.LayerEdgeRPC.SuperProofContract, p.SuperProof.Root)
+	if err != nil {
+		return fmt.Errorf("error checking super proof root on LayerEdge: %w", err)
+	}
+	if !exists {
+		return fmt.Errorf("super proof root %s not found in super proof contract", p.SuperProof.Root)
+	}
+
+	return nil
+}
@@ -0,0 +1,266 @@
+package da
+
+import (
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/Layer-Edge/bitcoin-da/merkle"
+	"github.com/Layer-Edge/bitcoin-da/models"
+)
+
+// fixtureAggregate returns an aggregate of n proofs stored the way
+// storeChunk stores them, and the leaf hash of each proof
+func fixtureAggregate(t *testing.T, id string, n int) (*models.AggregatedProof, []string) {
+	t.Helper()
+
+	proofs := make([]string, n)
+	leaves := make([]string, n)
+	for i := range proofs {
+		raw := []byte(fmt.Sprintf("%s-proof-%d", id, i))
+		proofs[i] = "0x" + hex.EncodeToString(raw)
+		leaves[i] = merkle.HashLeaf(raw).Hex()
+	}
+
+	prf := &ZKProof{}
+	root := prf.GenerateAggregatedProof(leaves)
+	if root == "" {
+		t.Fatalf("no root for %d leaves", n)
+	}
+	return &models.AggregatedProof{ID: id, MerkleRoot: root, Proofs: proofs}, leaves
+}
+
+func TestGenerateAggregatedProofMatchesTree(t *testing.T) {
+	for _, n := range []int{1, 2, 3, 5, 8} {
+		leaves := make([]string, n)
+		for i := range leaves {
+			leaves[i] = merkle.HashLeaf([]byte{byte('a' + i)}).Hex()
+		}
+		tree, err := merkle.FromHex(leaves)
+		if err != nil {
+			t.Fatalf("FromHex(%d): %v", n, err)
+		}
+
+		prf := &ZKProof{}
+		if got := prf.GenerateAggregatedProof(leaves); got != tree.Root().Hex() {
+			t.Errorf("%d leaves: GenerateAggregatedProof = %s, tree root = %s", n, got, tree.Root().Hex())
+		}
+		if got, err := (NativeMerkleBackend{}).Root(leaves); err != nil || got != tree.Root().Hex() {
+			t.Errorf("%d leaves: native root = %s, %v, want %s", n, got, err, tree.Root().Hex())
+		}
+	}
+}
+
+func TestBuildInclusionProof(t *testing.T) {
+	for _, n := range []int{1, 2, 3, 7} {
+		t.Run(fmt.Sprintf("%d proofs", n), func(t *testing.T) {
+			ap, leaves := fixtureAggregate(t, "agg", n)
+
+			for index, leaf := range leaves {
+				proof, err := BuildInclusionProof(ap, leaf)
+				if err != nil {
+					t.Fatalf("BuildInclusionProof(%d): %v", index, err)
+				}
+				if proof.Index != index || proof.Root != ap.MerkleRoot || proof.Leaf != leaf {
+					t.Errorf("proof %d = index %d, root %s, leaf %s", index, proof.Index, proof.Root, proof.Leaf)
+				}
+				if err := proof.Verify(); err != nil {
+					t.Errorf("proof %d does not verify: %v", index, err)
+				}
+
+				for i := range proof.Siblings {
+					tampered := *proof
+					tampered.Siblings = append([]string{}, proof.Siblings...)
+					tampered.Siblings[i] = merkle.HashLeaf([]byte("tampered")).Hex()
+					if err := tampered.Verify(); err == nil {
+						t.Errorf("proof %d verifies with sibling %d tampered", index, i)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestBuildInclusionProofDuplicateProofs(t *testing.T) {
+	raw := []byte("same proof")
+	leaf := merkle.HashLeaf(raw).Hex()
+	other := merkle.HashLeaf([]byte("other proof")).Hex()
+	prf := &ZKProof{}
+	ap := &models.AggregatedProof{
+		ID:         "dup",
+		MerkleRoot: prf.GenerateAggregatedProof([]string{leaf, other, leaf}),
+		Proofs:     []string{"0x" + hex.EncodeToString(raw), "0x" + hex.EncodeToString([]byte("other proof")), "0x" + hex.EncodeToString(raw)},
+	}
+
+	proof, err := BuildInclusionProof(ap, leaf)
+	if err != nil {
+		t.Fatalf("BuildInclusionProof: %v", err)
+	}
+	if proof.Index != 0 {
+		t.Errorf("index = %d, want the first occurrence", proof.Index)
+	}
+	if err := proof.Verify(); err != nil {
+		t.Errorf("proof does not verify: %v", err)
+	}
+}
+
+func TestBuildInclusionProofErrors(t *testing.T) {
+	ap, leaves := fixtureAggregate(t, "agg", 3)
+
+	wrongRoot := *ap
+	wrongRoot.MerkleRoot = merkle.HashLeaf([]byte("wrong root")).Hex()
+
+	badProof := *ap
+	badProof.Proofs = append([]string{"0xzz"}, ap.Proofs[1:]...)
+
+	tests := []struct {
+		name string
+		ap   *models.AggregatedProof
+		leaf string
+	}{
+		{name: "stored root does not match", ap: &wrongRoot, leaf: leaves[0]},
+		{name: "leaf not in the aggregate", ap: ap, leaf: merkle.HashLeaf([]byte("missing")).Hex()},
+		{name: "invalid leaf", ap: ap, leaf: "0x1234"},
+		{name: "invalid stored proof", ap: &badProof, leaf: leaves[1]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := BuildInclusionProof(tt.ap, tt.leaf); err == nil {
+				t.Fatal("BuildInclusionProof did not fail")
+			}
+		})
+	}
+}
+
+// fixtureSuperProof returns a super proof over the roots of aggregates, as
+// anchored on Bitcoin
+func fixtureSuperProof(t *testing.T, aggregates ...*models.AggregatedProof) *models.AggregatedProof {
+	t.Helper()
+
+	roots := make([]string, len(aggregates))
+	for i, ap := range aggregates {
+		roots[i] = ap.MerkleRoot
+	}
+	prf := &ZKProof{}
+	btcTx, btcBlock := "btc-tx", int64(840000)
+	return &models.AggregatedProof{
+		ID:              "super",
+		MerkleRoot:      prf.GenerateAggregatedProof(roots),
+		Proofs:          roots,
+		TransactionHash: "0xsupertx",
+		BTCTxHash:       &btcTx,
+		BTCBlockNumber:  &btcBlock,
+	}
+}
+
+func TestBuildAnchoredInclusionProof(t *testing.T) {
+	first, leaves := fixtureAggregate(t, "first", 3)
+	first.TransactionHash = "0xfirsttx"
+	second, _ := fixtureAggregate(t, "second", 2)
+	third, _ := fixtureAggregate(t, "third", 1)
+	super := fixtureSuperProof(t, second, first, third)
+
+	for _, leaf := range leaves {
+		proof, err := buildAnchoredInclusionProof(first, leaf, func(root string) (*models.AggregatedProof, error) {
+			if root != first.MerkleRoot {
+				t.Errorf("looked up super proof of %s, want %s", root, first.MerkleRoot)
+			}
+			return super, nil
+		})
+		if err != nil {
+			t.Fatalf("buildAnchoredInclusionProof: %v", err)
+		}
+
+		if err := proof.Verify(super.MerkleRoot); err != nil {
+			t.Errorf("proof of %s does not verify: %v", leaf, err)
+		}
+		if proof.Aggregate.Root != first.MerkleRoot || proof.SuperProof.Leaf != first.MerkleRoot || proof.SuperProof.Index != 1 {
+			t.Errorf("proof of %s does not chain through the aggregate root: %+v", leaf, proof)
+		}
+		if proof.AggregateTxHash != "0xfirsttx" || proof.SuperProofTxHash != "0xsupertx" {
+			t.Errorf("tx hashes = %s, %s", proof.AggregateTxHash, proof.SuperProofTxHash)
+		}
+		if proof.BTCTxHash == nil || *proof.BTCTxHash != "btc-tx" || proof.BTCBlockNumber == nil || *proof.BTCBlockNumber != 840000 {
+			t.Errorf("btc anchor = %v, %v", proof.BTCTxHash, proof.BTCBlockNumber)
+		}
+
+		if err := proof.Verify(first.MerkleRoot); err == nil {
+			t.Error("proof verifies against the aggregate root instead of the super proof root")
+		}
+		tampered := *proof
+		superLevel := *proof.SuperProof
+		superLevel.Siblings = append([]string{}, proof.SuperProof.Siblings...)
+		superLevel.Siblings[0] = merkle.HashLeaf([]byte("tampered")).Hex()
+		tampered.SuperProof = &superLevel
+		if err := tampered.Verify(super.MerkleRoot); err == nil {
+			t.Error("proof verifies with a tampered super proof sibling")
+		}
+		tampered = *proof
+		tampered.Aggregate.Siblings = append([]string{}, proof.Aggregate.Siblings...)
+		tampered.Aggregate.Siblings[0] = merkle.HashLeaf([]byte("tampered")).Hex()
+		if err := tampered.Verify(super.MerkleRoot); err == nil {
+			t.Error("proof verifies with a tampered aggregate sibling")
+		}
+	}
+}
+
+func TestBuildAnchoredInclusionProofWithoutSuperProof(t *testing.T) {
+	ap, leaves := fixtureAggregate(t, "agg", 2)
+	other, _ := fixtureAggregate(t, "other", 2)
+	lookupErr := errors.New("connection refused")
+
+	tests := []struct {
+		name       string
+		superProof func(root string) (*models.AggregatedProof, error)
+		err        bool
+	}{
+		{
+			name:       "not rolled into a super proof yet",
+			superProof: func(string) (*models.AggregatedProof, error) { return nil, sql.ErrNoRows },
+		},
+		{
+			name:       "aggregate missing from the super proof",
+			superProof: func(string) (*models.AggregatedProof, error) { return fixtureSuperProof(t, other), nil },
+			err:        true,
+		},
+		{
+			name: "super proof root does not match its aggregates",
+			superProof: func(string) (*models.AggregatedProof, error) {
+				super := fixtureSuperProof(t, ap, other)
+				super.MerkleRoot = other.MerkleRoot
+				return super, nil
+			},
+			err: true,
+		},
+		{
+			name:       "lookup fails",
+			superProof: func(string) (*models.AggregatedProof, error) { return nil, lookupErr },
+			err:        true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proof, err := buildAnchoredInclusionProof(ap, leaves[0], tt.superProof)
+			if tt.err {
+				if err == nil {
+					t.Fatal("buildAnchoredInclusionProof did not fail")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("buildAnchoredInclusionProof: %v", err)
+			}
+			if proof.SuperProof != nil {
+				t.Errorf("super proof = %+v, want none", proof.SuperProof)
+			}
+			if err := proof.Aggregate.Verify(); err != nil {
+				t.Errorf("aggregate level does not verify: %v", err)
+			}
+			if err := proof.Verify(ap.MerkleRoot); err == nil {
+				t.Error("proof without a super proof verifies")
+			}
+		})
+	}
+}
@@ -0,0 +1,345 @@
+package da
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/Layer-Edge/bitcoin-da/config"
+	"github.com/Layer-Edge/bitcoin-da/merkle"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+const (
+	AnchorModeOPReturn    = "op-return"
+	AnchorModeInscription = "inscription"
+
+	// inscriptionTag disambiguates our envelopes from other inscriptions
+	inscriptionTag = "block"
+	// maxInscriptionSize keeps the reveal below the 400k weight standardness limit
+	maxInscriptionSize = 390000
+)
+
+// inscriptionKey signs the tapscript spend of the commit output, see
+// `inscription.private-key` in the config
+var (
+	inscriptionKey     *btcec.PrivateKey
+	inscriptionPostage = int64(dustLimitSats)
+)
+
+// Inscription holds the tapscript and derived taproot output for a payload
+type Inscription struct {
+	Script       []byte
+	Leaf         txscript.TapLeaf
+	ControlBlock []byte
+	PkScript     []byte
+}
+
+// InitInscription loads the tapscript key from the config. It is a no-op if
+// no job anchors with inscriptions.
+func InitInscription(cfg *config.Config) {
+	if cfg.Inscription.PrivateKey == "" {
+		return
+	}
+
+	keyBytes, err := hex.DecodeString(cfg.Inscription.PrivateKey)
+	if err != nil || len(keyBytes) != 32 {
+		log.Fatalf("Invalid inscription private key, expected 32 hex-encoded bytes")
+	}
+	inscriptionKey, _ = btcec.PrivKeyFromBytes(keyBytes)
+	inscriptionPostage = cfg.Inscription.PostageSats
+}
+
+// InscriptionEnvelope builds the tapscript
+//
+//	<pubkey> OP_CHECKSIG OP_FALSE OP_IF "block" OP_1 <height> OP_0 <chunks> OP_ENDIF
+//
+// where data is split into 520 byte pushes
+func InscriptionEnvelope(pubKey *btcec.PublicKey, height uint64, data []byte) []byte {
+	// The script is far larger than txscript.ScriptBuilder allows, so the
+	// pushes are encoded by hand
+	script := make([]byte, 0, len(data)+len(data)/txscript.MaxScriptElementSize*3+64)
+	script = appendPush(script, schnorr.SerializePubKey(pubKey))
+	script = append(script, txscript.OP_CHECKSIG, txscript.OP_FALSE, txscript.OP_IF)
+	script = appendPush(script, []byte(inscriptionTag))
+	script = append(script, txscript.OP_1)
+	script = appendPush(script, heightBytes(height))
+	script = append(script, txscript.OP_0)
+	for len(data) > 0 {
+		n := min(len(data), txscript.MaxScriptElementSize)
+		script = appendPush(script, data[:n])
+		data = data[n:]
+	}
+	return append(script, txscript.OP_ENDIF)
+}
+
+// appendPush appends the canonical push of data to script
+func appendPush(script []byte, data []byte) []byte {
+	switch n := len(data); {
+	case n <= txscript.OP_DATA_75:
+		script = append(script, byte(n))
+	case n <= 0xff:
+		script = append(script, txscript.OP_PUSHDATA1, byte(n))
+	default:
+		script = append(script, txscript.OP_PUSHDATA2, byte(n), byte(n>>8))
+	}
+	return append(script, data...)
+}
+
+// heightBytes encodes height as minimal little-endian bytes
+func heightBytes(height uint64) []byte {
+	buf := make([]byte, 8)
+	binary.LittleEndian.PutUint64(buf, height)
+	return bytes.TrimRight(buf, "\x00")
+}
+
+// NewInscription derives the single-leaf taproot output committing to the envelope
+func NewInscription(key *btcec.PrivateKey, height uint64, data []byte) (*Inscription, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("empty inscription payload")
+	}
+	if len(data) > maxInscriptionSize {
+		return nil, fmt.Errorf("inscription payload of %d bytes exceeds the %d byte limit", len(data), maxInscriptionSize)
+	}
+
+	script := InscriptionEnvelope(key.PubKey(), height, data)
+	leaf := txscript.NewBaseTapLeaf(script)
+	tree := txscript.AssembleTaprootScriptTree(leaf)
+
+	controlBlock := tree.LeafMerkleProofs[0].ToControlBlock(key.PubKey())
+	controlBlockBytes, err := controlBlock.ToBytes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize control block: %w", err)
+	}
+
+	rootHash := tree.RootNode.TapHash()
+	outputKey := txscript.ComputeTaprootOutputKey(key.PubKey(), rootHash[:])
+	pkScript, err := txscript.PayToTaprootScript(outputKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build taproot output: %w", err)
+	}
+
+	return &Inscription{
+		Script:       script,
+		Leaf:         leaf,
+		ControlBlock: controlBlockBytes,
+		PkScript:     pkScript,
+	}, nil
+}
+
+// InscriptionPayload serializes an aggregate for inscription as a
+// PayloadTypeRootLeaves anchor payload: the 32 byte root followed by every
+// 32 byte leaf it commits to
+func InscriptionPayload(protocolId string, root string, leaves []string) ([]byte, error) {
+	rootHash, err := merkle.ParseLeaf(root)
+	if err != nil {
+		return nil, fmt.Errorf("invalid root: %w", err)
+	}
+	leafHashes, err := merkle.ParseLeaves(leaves)
+	if err != nil {
+		return nil, err
+	}
+
+	body := make([]byte, 0, 32*(len(leafHashes)+1))
+	body = append(body, rootHash.Bytes()...)
+	for _, leaf := range leafHashes {
+		body = append(body, leaf.Bytes()...)
+	}
+	return EncodeAnchorPayload(protocolId, PayloadTypeRootLeaves, body), nil
+}
+
+// ProcessBTCInscription inscribes root and its leaves and returns the reveal
+// transaction hash, which is the transaction carrying the data
+func ProcessBTCInscription(root string, leaves []string, protocolId string) ([]byte, error) {
+	data, err := InscriptionPayload(protocolId, root, leaves)
+	if err != nil {
+		return nil, err
+	}
+
+	height, err := GetBlockCount()
+	if err != nil {
+		return nil, err
+	}
+
+	hash, err := CreateInscriptionTransaction(uint64(height), data)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(hash), nil
+}
+
+// CreateInscriptionTransaction funds a commit output locking to the
+// envelope for data, then signs and broadcasts the reveal that spends it.
+// It returns the reveal transaction hash.
+func CreateInscriptionTransaction(height uint64, data []byte) (string, error) {
+	if inscriptionKey == nil {
+		return "", fmt.Errorf("inscription private key is not configured")
+	}
+
+	log.Printf("Creating inscription with %d bytes of data at height %d", len(data), height)
+
+	insc, err := NewInscription(inscriptionKey, height, data)
+	if err != nil {
+		return "", err
+	}
+
+	walletSpendMutex.Lock()
+	defer walletSpendMutex.Unlock()
+
+	if !UnlockWallet() {
+		return "", fmt.Errorf("failed to unlock wallet")
+	}
+
+	feeRate := CurrentFeeRate()
+
+	// The reveal pays its postage back to the wallet on a fresh address
+	revealScript, err := walletScriptPubKey(GetRawAddress())
+	if err != nil {
+		return "", err
+	}
+
+	reveal := wire.NewMsgTx(wire.TxVersion)
+	reveal.AddTxIn(&wire.TxIn{
+		Sequence: wire.MaxTxInSequenceNum - 2, // signal BIP125 replaceability
+		Witness:  wire.TxWitness{make([]byte, schnorr.SignatureSize), insc.Script, insc.ControlBlock},
+	})
+	reveal.AddTxOut(wire.NewTxOut(inscriptionPostage, revealScript))
+
+	revealFee := FeeForVsize(msgTxVsize(reveal), feeRate)
+	commitValue := inscriptionPostage + revealFee
+
+	// Step 1: Fund, sign and broadcast the commit
+	commit, commitVout, err := fundCommit(insc.PkScript, commitValue, feeRate)
+	if err != nil {
+		return "", err
+	}
+	commitHash := commit.TxHash()
+
+	// Step 2: Sign the reveal against the commit output
+	reveal.TxIn[0].PreviousOutPoint = *wire.NewOutPoint(&commitHash, commitVout)
+	prevFetcher := txscript.NewCannedPrevOutputFetcher(insc.PkScript, commitValue)
+	sigHashes := txscript.NewTxSigHashes(reveal, prevFetcher)
+	sig, err := txscript.RawTxInTapscriptSignature(reveal, sigHashes, 0, commitValue, insc.PkScript,
+		insc.Leaf, txscript.SigHashDefault, inscriptionKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign reveal: %w", err)
+	}
+	reveal.TxIn[0].Witness[0] = sig
+
+	vm, err := txscript.NewEngine(insc.PkScript, reveal, 0, txscript.StandardVerifyFlags, nil,
+		sigHashes, commitValue, prevFetcher)
+	if err == nil {
+		err = vm.Execute()
+	}
+	if err != nil {
+		return "", fmt.Errorf("reveal failed script validation: %w", err)
+	}
+
+	var commitBuf, revealBuf bytes.Buffer
+	if err := commit.Serialize(&commitBuf); err != nil {
+		return "", fmt.Errorf("failed to serialize commit: %w", err)
+	}
+	if err := reveal.Serialize(&revealBuf); err != nil {
+		return "", fmt.Errorf("failed to serialize reveal: %w", err)
+	}
+
+	commitTxHash := SendSignedTransaction(hex.EncodeToString(commitBuf.Bytes()))
+	if commitTxHash == "" {
+		return "", fmt.Errorf("failed to broadcast commit transaction")
+	}
+	log.Printf("Broadcast inscription commit %s (%d sats to %x)", commitTxHash, commitValue, insc.PkScript)
+
+	// Step 3: Broadcast the reveal
+	revealTxHash := SendSignedTransaction(hex.EncodeToString(revealBuf.Bytes()))
+	if revealTxHash == "" {
+		// The commit output stays spendable with the inscription key
+		return "", fmt.Errorf("failed to broadcast reveal for commit %s", commitTxHash)
+	}
+
+	log.Printf("Successfully created inscription: commit %s, reveal %s (reveal fee %d sats)", commitTxHash, revealTxHash, revealFee)
+	return revealTxHash, nil
+}
+
+// fundCommit lets the wallet fund and sign a transaction paying value to
+// pkScript and returns it with the index of that output
+func fundCommit(pkScript []byte, value int64, feeRate float64) (*wire.MsgTx, uint32, error) {
+	unfunded := wire.NewMsgTx(wire.TxVersion)
+	unfunded.AddTxOut(wire.NewTxOut(value, pkScript))
+	var buf bytes.Buffer
+	if err := unfunded.Serialize(&buf); err != nil {
+		return nil, 0, fmt.Errorf("failed to serialize commit: %w", err)
+	}
+
+	result, err := callRPC("fund_commit", "fundrawtransaction", []interface{}{
+		hex.EncodeToString(buf.Bytes()),
+		map[string]interface{}{
+			"fee_rate":    feeRate,
+			"replaceable": true,
+		},
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	var funded struct {
+		Hex string `json:"hex"`
+	}
+	if err := json.Unmarshal([]byte(result), &funded); err != nil {
+		return nil, 0, fmt.Errorf("failed to unmarshal fundrawtransaction result: %w", err)
+	}
+
+	signed := SignRawTransaction(funded.Hex)
+	if signed == "" {
+		return nil, 0, fmt.Errorf("failed to sign commit transaction")
+	}
+	var sgn signedtx
+	if err := json.Unmarshal([]byte(signed), &sgn); err != nil {
+		return nil, 0, fmt.Errorf("failed to unmarshal signed commit: %w", err)
+	}
+
+	raw, err := hex.DecodeString(sgn.Hex)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid commit hex: %w", err)
+	}
+	var commit wire.MsgTx
+	if err := commit.Deserialize(bytes.NewReader(raw)); err != nil {
+		return nil, 0, fmt.Errorf("failed to deserialize commit: %w", err)
+	}
+
+	for i, out := range commit.TxOut {
+		if out.Value == value && bytes.Equal(out.PkScript, pkScript) {
+			return &commit, uint32(i), nil
+		}
+	}
+	return nil, 0, fmt.Errorf("funded commit is missing the inscription output")
+}
+
+// walletScriptPubKey returns the scriptPubKey of a wallet address
+func walletScriptPubKey(address string) ([]byte, error) {
+	if address == "" {
+		return nil, fmt.Errorf("failed to get wallet address")
+	}
+
+	result, err := callRPC("address_info", "getaddressinfo", []interface{}{address})
+	if err != nil {
+		return nil, err
+	}
+	var info struct {
+		ScriptPubKey string `json:"scriptPubKey"`
+	}
+	if err := json.Unmarshal([]byte(result), &info); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal address info: %w", err)
+	}
+	return hex.DecodeString(info.ScriptPubKey)
+}
+
+// msgTxVsize returns the virtual size of tx
+func msgTxVsize(tx *wire.MsgTx) int64 {
+	weight := tx.SerializeSizeStripped()*3 + tx.SerializeSize()
+	return int64((weight + 3) / 4)
+}
@@ -4,15 +4,51 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"log"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/Layer-Edge/bitcoin-da/config"
+	"github.com/Layer-Edge/bitcoin-da/merkle"
+	"github.com/Layer-Edge/bitcoin-da/utils"
 )
 
 // var cfg = config.GetConfig() // Removed to avoid flag parsing during tests
 
+// Merkle tree backends selectable through `merkle-tree-mode`
+const (
+	MerkleModeNative     = "native"
+	MerkleModeRemote     = "remote"
+	MerkleModeCrossCheck = "cross-check"
+)
+
+// MerkleBackend computes the root that is stored on LayerEdge for a list of
+// hex encoded leaves
+type MerkleBackend interface {
+	Root(leaves []string) (string, error)
+}
+
+// NativeMerkleBackend builds the tree in-process with the merkle package
+type NativeMerkleBackend struct{}
+
+// RemoteMerkleBackend delegates tree construction to the external
+// merkle-tree-generator service
+type RemoteMerkleBackend struct {
+	Server string
+	client *http.Client
+}
+
+// CrossCheckMerkleBackend builds the tree natively and compares the result
+// with the remote service. The native root is always returned; mismatches
+// and remote failures are only reported.
+type CrossCheckMerkleBackend struct {
+	Native NativeMerkleBackend
+	Remote *RemoteMerkleBackend
+}
+
 type ZKProof struct {
+	backend MerkleBackend
 }
 
 type ProcessRequest struct {
@@ -31,13 +67,82 @@ type ProcessResponse struct {
 	} `json:"visualization"`
 }
 
-func GetMerkleRoot(input string) (string, error) {
-	dataArray := strings.Split(input, ",")
+// NewMerkleBackend returns the backend selected by the config, defaulting to
+// the native builder
+func NewMerkleBackend(cfg *config.Config) MerkleBackend {
+	switch cfg.MerkleTreeMode {
+	case MerkleModeRemote:
+		return NewRemoteMerkleBackend(cfg.MerkleTreeGeneratorServer)
+	case MerkleModeCrossCheck:
+		return &CrossCheckMerkleBackend{
+			Remote: NewRemoteMerkleBackend(cfg.MerkleTreeGeneratorServer),
+		}
+	default:
+		return NativeMerkleBackend{}
+	}
+}
+
+// NewZKProof creates a ZKProof using the configured merkle backend
+func NewZKProof(cfg *config.Config) ZKProof {
+	return ZKProof{backend: NewMerkleBackend(cfg)}
+}
 
+func (NativeMerkleBackend) Root(leaves []string) (string, error) {
+	tree, err := merkle.FromHex(leaves)
+	if err != nil {
+		return "", fmt.Errorf("error building merkle tree: %w", err)
+	}
+	return tree.Root().Hex(), nil
+}
+
+func NewRemoteMerkleBackend(server string) *RemoteMerkleBackend {
+	return &RemoteMerkleBackend{
+		Server: server,
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (b *RemoteMerkleBackend) Root(leaves []string) (string, error) {
+	return GetMerkleRoot(b.client, b.Server, leaves)
+}
+
+func (b *CrossCheckMerkleBackend) Root(leaves []string) (string, error) {
+	root, err := b.Native.Root(leaves)
+	if err != nil {
+		return "", err
+	}
+
+	remoteRoot, err := b.Remote.Root(leaves)
+	if err != nil {
+		utils.LogProcessingError("MerkleCrossCheck", "Remote merkle tree generator failed", err, map[string]interface{}{
+			"leaves": len(leaves),
+		})
+		return root, nil
+	}
+
+	if normalizeRoot(remoteRoot) != normalizeRoot(root) {
+		utils.LogProcessingError("MerkleCrossCheck", "Merkle root mismatch between native and remote backends",
+			fmt.Errorf("native %s != remote %s", root, remoteRoot), map[string]interface{}{
+				"leaves": len(leaves),
+			})
+	} else {
+		log.Printf("Merkle cross-check passed for %d leaves: %s", len(leaves), root)
+	}
+
+	return root, nil
+}
+
+func normalizeRoot(root string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(root), "0x"))
+}
+
+// GetMerkleRoot asks the merkle-tree-generator service at server for the root
+// of the given leaves
+func GetMerkleRoot(client *http.Client, server string, leaves []string) (string, error) {
 	// Create request payload
 	reqData := ProcessRequest{
 		Operation: "insert",
-		Data:      dataArray,
+		Data:      leaves,
 	}
 
 	jsonData, err := json.Marshal(reqData)
@@ -45,12 +150,9 @@ func GetMerkleRoot(input string) (string, error) {
 		return "", fmt.Errorf("error creating JSON request: %v", err)
 	}
 
-	// Get config when needed
-	cfg := config.GetConfig()
-
 	// Make POST request
-	resp, err := http.Post(
-		cfg.MerkleTreeGeneratorServer+"/process",
+	resp, err := client.Post(
+		server+"/process",
 		"application/json",
 		bytes.NewBuffer(jsonData),
 	)
@@ -72,14 +174,15 @@ func GetMerkleRoot(input string) (string, error) {
 	return processResp.Root, nil
 }
 
-// MD5 sum for now
-func (prf *ZKProof) GenerateAggregatedProof(msg string) string {
-	/* struct {
-	       byte[2] length
-	       data
-	   }
-	*/
-	merkleRoot, err := GetMerkleRoot(msg)
+// GenerateAggregatedProof returns the merkle root of the given leaves, or an
+// empty string if the backend fails
+func (prf *ZKProof) GenerateAggregatedProof(leaves []string) string {
+	backend := prf.backend
+	if backend == nil {
+		backend = NativeMerkleBackend{}
+	}
+
+	merkleRoot, err := backend.Root(leaves)
 	if err != nil {
 		fmt.Printf("Error generating Merkle root: %v\n", err)
 		return ""
@@ -0,0 +1,75 @@
+package da
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Layer-Edge/bitcoin-da/models"
+	"github.com/Layer-Edge/bitcoin-da/utils"
+)
+
+// A namespace identifies the chain a proof was submitted for. Labels on the
+// data socket follow the "<chain>-chain-" convention (e.g. "u2u-chain-"), and
+// the namespace is the chain part.
+var namespacePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+// namespaceInvalidChars are the runs of characters a label may hold that a
+// namespace may not
+var namespaceInvalidChars = regexp.MustCompile(`[^a-z0-9_-]+`)
+
+const maxNamespaceLength = 64
+
+// NamespaceFromLabel derives the namespace of a data socket label. Any label
+// yields a valid namespace: other characters become '-', and labels that are
+// too long, or have nothing left, end in a hash of the label so distinct
+// labels keep distinct namespaces.
+func NamespaceFromLabel(label string) string {
+	namespace := strings.Trim(strings.ToLower(strings.TrimSpace(label)), "-")
+	namespace = strings.TrimSuffix(namespace, "-chain")
+	if namespacePattern.MatchString(namespace) {
+		return namespace
+	}
+
+	namespace = namespaceInvalidChars.ReplaceAllString(namespace, "-")
+	namespace = strings.TrimLeft(namespace, "-_")
+	if namespacePattern.MatchString(namespace) {
+		return namespace
+	}
+
+	// 16 hex characters of the label hash, after as much of the label as fits
+	hash := strings.TrimPrefix(utils.Keccak256Hash([]byte(label)), "0x")[:16]
+	if namespace == "" {
+		return "label-" + hash
+	}
+	if len(namespace) > maxNamespaceLength-len(hash)-1 {
+		namespace = namespace[:maxNamespaceLength-len(hash)-1]
+	}
+	return namespace + "-" + hash
+}
+
+// ValidateNamespace checks that namespace is a lowercase chain identifier
+func ValidateNamespace(namespace string) error {
+	if !namespacePattern.MatchString(namespace) {
+		return fmt.Errorf("invalid namespace %q: expected 1-64 characters of a-z, 0-9, '-' or '_'", namespace)
+	}
+	return nil
+}
+
+// NamespaceLeafIndexes returns the leaf indexes of each namespace inside the
+// aggregate with the given root
+func NamespaceLeafIndexes(root string) (map[string][]int, error) {
+	proofs, err := models.GetPendingProofsByAggregateRoot(root)
+	if err != nil {
+		return nil, err
+	}
+
+	indexes := map[string][]int{}
+	for _, proof := range proofs {
+		if proof.LeafIndex == nil {
+			continue
+		}
+		indexes[proof.Namespace] = append(indexes[proof.Namespace], *proof.LeafIndex)
+	}
+	return indexes, nil
+}
@@ -0,0 +1,44 @@
+package da
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNamespaceFromLabel(t *testing.T) {
+	long := strings.Repeat("chain", 20)
+
+	tests := []struct {
+		label string
+		want  string
+	}{
+		{label: "u2u-chain-", want: "u2u"},
+		{label: "  Polygon-Chain- ", want: "polygon"},
+		{label: "my_chain", want: "my_chain"},
+		{label: "base.sepolia", want: "base-sepolia"},
+		{label: "eth mainnet-chain-", want: "eth-mainnet"},
+		{label: "_private", want: "private"},
+	}
+	for _, tt := range tests {
+		if got := NamespaceFromLabel(tt.label); got != tt.want {
+			t.Errorf("NamespaceFromLabel(%q) = %q, want %q", tt.label, got, tt.want)
+		}
+	}
+
+	// Labels without a usable namespace of their own end in a hash of the label
+	for _, label := range []string{"", "...", "@@@", long, long + "x", strings.Repeat("ü", 40)} {
+		namespace := NamespaceFromLabel(label)
+		if err := ValidateNamespace(namespace); err != nil {
+			t.Errorf("NamespaceFromLabel(%q) = %q: %v", label, namespace, err)
+		}
+		if namespace != NamespaceFromLabel(label) {
+			t.Errorf("NamespaceFromLabel(%q) is not deterministic", label)
+		}
+	}
+	if NamespaceFromLabel(long) == NamespaceFromLabel(long+"x") {
+		t.Error("long labels with the same prefix share a namespace")
+	}
+	if NamespaceFromLabel("...") == NamespaceFromLabel("@@@") {
+		t.Error("labels without valid characters share a namespace")
+	}
+}
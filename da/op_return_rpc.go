@@ -3,16 +3,18 @@ package da
 import (
 	"bytes"
 	"context"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
 	"log"
-	"math"
 	"net/http"
 	"sync"
 	"time"
 
 	"github.com/Layer-Edge/bitcoin-da/utils"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
 )
 
 var (
@@ -27,6 +29,8 @@ var (
 	backoffFactor  = 2.0
 	requestTimeout = 30 * time.Second
 
+	maxLoggedResponse = 2048
+
 	// Circuit breaker configuration
 	circuitTimeout = 60 * time.Second
 	maxFailures    = 5
@@ -41,11 +45,22 @@ type response struct {
 	ID string `json:"id"`
 }
 
+// RPCError is an error reported by bitcoind in a JSON-RPC response
+type RPCError struct {
+	Code    int
+	Message string
+}
+
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("RPC error %d: %s", e.Code, e.Message)
+}
+
 type utxo struct {
-	Txid    string  `json:"txid"`
-	Vout    int     `json:"vout"`
-	Amount  float64 `json:"amount"`
-	Address string  `json:"address,omitempty"`
+	Txid         string  `json:"txid"`
+	Vout         int     `json:"vout"`
+	Amount       float64 `json:"amount"`
+	Address      string  `json:"address,omitempty"`
+	ScriptPubKey string  `json:"scriptPubKey,omitempty"`
 }
 
 type signedtx struct {
@@ -177,10 +192,21 @@ func makeRPCCallWithTimeout(url string, args []byte) (string, error) {
 	}
 
 	if resp.StatusCode != http.StatusOK {
+		// bitcoind reports RPC errors with an error status and the error in the body
+		var failed response
+		if json.Unmarshal(body, &failed) == nil && failed.Error != nil {
+			return "", fmt.Errorf("BTC API returned non-OK status: %d: %w", resp.StatusCode,
+				&RPCError{Code: failed.Error.Code, Message: failed.Error.Message})
+		}
 		return "", fmt.Errorf("BTC API returned non-OK status: %d, body: %s", resp.StatusCode, string(body))
 	}
 
-	log.Printf("Successfully sent RPC: %s", string(body))
+	// Raw blocks and transactions can be megabytes long, only log the head
+	logged := string(body)
+	if len(logged) > maxLoggedResponse {
+		logged = logged[:maxLoggedResponse] + "...(truncated)"
+	}
+	log.Printf("Successfully sent RPC: %s", logged)
 	result := ExtractResult(string(body))
 	return result, nil
 }
@@ -223,9 +249,6 @@ func ListUnspent() string {
 			9999999,
 			[]interface{}{},
 			true,
-			map[string]int{
-				"maximumCount": 10,
-			},
 		},
 	}
 	jsonPayload, err := json.Marshal(payload)
@@ -271,67 +294,62 @@ func GetRawAddress() string {
 	return result
 }
 
-func CalculateRequired(numInputs int, dataSize int) float64 {
-	return float64(53+numInputs*68+dataSize) * float64(0.00000001)
+// CalculateRequired returns the fee in BTC for an anchor spending inputs with
+// dataSize bytes of OP_RETURN data at feeRate sat/vB
+func CalculateRequired(inputs []utxo, dataSize int, feeRate float64) float64 {
+	return satsToBTC(FeeForVsize(EstimateTxVsize(inputs, dataSize), feeRate))
 }
 
-func FilterUTXOs(unspent string, length int) ([]map[string]interface{}, float64, string) {
+// FilterUTXOs selects inputs covering the fee for dataSize bytes of OP_RETURN
+// data at feeRate sat/vB using the configured coin selection strategy. It
+// returns the inputs, the change in BTC, a fresh change address (empty when
+// the change would be dust and is left to fees) and the fee in satoshis.
+func FilterUTXOs(unspent string, dataSize int, feeRate float64) ([]map[string]interface{}, float64, string, int64) {
 	inputs := []map[string]interface{}{}
 	if unspent == "" {
-		return inputs, 0.0, ""
+		return inputs, 0.0, "", 0
 	}
-	var t []json.RawMessage
-	err := json.Unmarshal([]byte(unspent), &t)
+	utxos, err := ParseUTXOs(unspent)
 	if err != nil {
-		log.Printf("Failed to unmarshal response: %v", err)
-		return inputs, 0.0, ""
+		log.Printf("Failed to parse UTXOs: %v", err)
+		return inputs, 0.0, "", 0
 	}
-	totalAmt := 0.0
-	numInputs := 0
-	required := 0.0
-	var changeAddress string
+	return selectInputs(coinSelectionStrategy, utxos, dataSize, feeRate)
+}
 
-	log.Printf("Found %d UTXOs to process", len(t))
+func selectInputs(strategy string, utxos []utxo, dataSize int, feeRate float64) ([]map[string]interface{}, float64, string, int64) {
+	inputs := []map[string]interface{}{}
 
-	for numInputs < len(t) {
-		var u utxo
-		err := json.Unmarshal(t[numInputs], &u)
-		if err != nil {
-			log.Printf("Failed to unmarshal response: %v", err)
-			return inputs, 0.0, ""
-		} else {
-			log.Printf("UTXO : %+v", u)
-		}
+	log.Printf("Found %d UTXOs to process", len(utxos))
 
-		log.Printf("Processing UTXO: txid=%s, vout=%d, amount=%f", u.Txid, u.Vout, u.Amount)
+	sel, err := SelectCoins(strategy, utxos, dataSize, feeRate)
+	if err != nil {
+		log.Printf("Coin selection (%s) failed: %v", strategy, err)
+		return inputs, 0.0, "", 0
+	}
 
-		inputData := map[string]interface{}{
+	for _, u := range sel.Inputs {
+		log.Printf("Selected UTXO: txid=%s, vout=%d, amount=%f", u.Txid, u.Vout, u.Amount)
+		inputs = append(inputs, map[string]interface{}{
 			"txid": u.Txid,
 			"vout": u.Vout,
-		}
-		inputs = append(inputs, inputData)
-		totalAmt += (float64(u.Amount) * 100000000)
-		required = (CalculateRequired(numInputs+1, length) * 100000000)
-
-		// Use the address from the first UTXO as change address
-		if numInputs == 0 && u.Address != "" {
-			changeAddress = u.Address
-		}
-
-		log.Printf("Current total: %f BTC, required: %f BTC", totalAmt, required)
+		})
+	}
 
-		if totalAmt >= required {
-			break
-		}
-		numInputs++
-		if numInputs >= 10 {
-			return []map[string]interface{}{}, 0.0, ""
+	// Never reuse an input address for change
+	var changeAddress string
+	if sel.Change > 0 {
+		changeAddress = GetRawAddress()
+		if changeAddress == "" {
+			log.Printf("Failed to get a fresh change address")
+			return []map[string]interface{}{}, 0.0, "", 0
 		}
 	}
-	change := (totalAmt - required) / float64(100000000)
-	rounded := math.Round(change*1e8) / 1e8
-	log.Printf("Inputs: %v, Change: %.8f, Change Address: %s", inputs, rounded, changeAddress)
-	return inputs, float64(rounded), changeAddress
+
+	change := satsToBTC(sel.Change)
+	log.Printf("Inputs: %v, Total: %d sats, Fee: %d sats (%.2f sat/vB), Change: %.8f, Change Address: %s",
+		inputs, sel.Total, sel.Fee, feeRate, change, changeAddress)
+	return inputs, change, changeAddress, sel.Fee
 }
 
 func CreateRawTransaction(inputs []map[string]interface{}, address string, change float64, data string) string {
@@ -340,8 +358,8 @@ func CreateRawTransaction(inputs []map[string]interface{}, address string, chang
 		return ""
 	}
 
-	if address == "" {
-		log.Printf("Empty address provided")
+	if change > 0 && address == "" {
+		log.Printf("Empty change address provided")
 		return ""
 	}
 
@@ -350,16 +368,22 @@ func CreateRawTransaction(inputs []map[string]interface{}, address string, chang
 
 	log.Printf("Creating raw transaction with %d inputs, change address %s, change amount %.8f BTC (%d satoshis)", len(inputs), address, change, changeSatoshis)
 
+	outputs := map[string]interface{}{
+		"data": data,
+	}
+	if change > 0 {
+		outputs[address] = change
+	}
+
 	payload := map[string]interface{}{
 		"jsonrpc": "1.0",
 		"id":      "op_cat_decode",
 		"method":  "createrawtransaction",
 		"params": []interface{}{
 			inputs,
-			map[string]interface{}{
-				"data":  data,
-				address: change,
-			},
+			outputs,
+			0,    // locktime
+			true, // signal BIP125 replaceability so stuck anchors can be fee bumped
 		},
 	}
 	// Print payload for debugging
@@ -481,58 +505,14 @@ func GetTransactionInfo(txHash string) (string, *int64) {
 
 	log.Printf("Getting transaction info for: %s", txHash)
 
-	payload := map[string]interface{}{
-		"jsonrpc": "1.0",
-		"id":      "get_tx_info",
-		"method":  "gettransaction",
-		"params": []interface{}{
-			txHash,
-		},
-	}
-	jsonPayload, err := json.Marshal(payload)
-	if err != nil {
-		log.Printf("Failed to marshal get transaction payload: %v", err)
-		return "", nil
-	}
-
-	response, err := RetryRPCCall(func() (string, error) {
-		return makeRPCCallWithTimeout(BTCEndpoint, jsonPayload)
-	})
-
+	tx, err := GetWalletTransaction(txHash)
 	if err != nil {
 		log.Printf("GetTransactionInfo RPC call failed: %v", err)
 		return "", nil
 	}
 
-	// Parse the response to extract block height
-	var txResponse struct {
-		Result struct {
-			Blockhash     string `json:"blockhash,omitempty"`
-			Blockheight   *int64 `json:"blockheight,omitempty"`
-			Blockindex    *int   `json:"blockindex,omitempty"`
-			Blocktime     *int64 `json:"blocktime,omitempty"`
-			Confirmations *int   `json:"confirmations,omitempty"`
-			Txid          string `json:"txid"`
-		} `json:"result"`
-		Error *struct {
-			Code    int    `json:"code"`
-			Message string `json:"message"`
-		} `json:"error"`
-	}
-
-	err = json.Unmarshal([]byte(response), &txResponse)
-	if err != nil {
-		log.Printf("Failed to unmarshal transaction response: %v", err)
-		return response, nil
-	}
-
-	if txResponse.Error != nil {
-		log.Printf("RPC error in gettransaction: code=%d, message=%s", txResponse.Error.Code, txResponse.Error.Message)
-		return response, nil
-	}
-
-	log.Printf("Transaction info retrieved - Block height: %v, Confirmations: %v", txResponse.Result.Blockheight, txResponse.Result.Confirmations)
-	return response, txResponse.Result.Blockheight
+	log.Printf("Transaction info retrieved - Block height: %v, Confirmations: %v", tx.Blockheight, tx.Confirmations)
+	return tx.Txid, tx.Blockheight
 }
 
 func ExtractResult(responseStr string) string {
@@ -563,53 +543,177 @@ func ExtractResult(responseStr string) string {
 }
 
 func CreateOPReturnTransaction(data string) string {
+	walletSpendMutex.Lock()
+	defer walletSpendMutex.Unlock()
+
+	txHash, _ := createOPReturnTransaction(data, nil, coinSelectionStrategy)
+	return txHash
+}
+
+// CreateLinkedOPReturnTransactions writes each part in its own anchor, each
+// spending the change of the previous one so that the last anchor can only
+// confirm together with every earlier part. It returns the last anchor's hash.
+func CreateLinkedOPReturnTransactions(parts []string) (string, error) {
+	walletSpendMutex.Lock()
+	defer walletSpendMutex.Unlock()
+
+	var txHash string
+	var change *utxo
+	for i, data := range parts {
+		var funding []utxo
+		if i > 0 {
+			if change == nil {
+				return "", fmt.Errorf("anchor %s for part %d of %d left no change to link the next part", txHash, i, len(parts))
+			}
+			funding = []utxo{*change}
+		}
+
+		// Largest-first keeps a change output for the next part to spend
+		txHash, change = createOPReturnTransaction(data, funding, CoinSelectionLargestFirst)
+		if txHash == "" {
+			return "", fmt.Errorf("failed to write part %d of %d", i+1, len(parts))
+		}
+		log.Printf("Wrote linked anchor part %d of %d: %s", i+1, len(parts), txHash)
+	}
+	return txHash, nil
+}
+
+// createOPReturnTransaction funds an anchor from funding, or from the whole
+// wallet if funding is nil, and returns its hash and change output. The
+// caller must hold walletSpendMutex.
+func createOPReturnTransaction(data string, funding []utxo, strategy string) (string, *utxo) {
 	log.Printf("Creating OP_RETURN transaction with data of length %d", len(data))
 
+	// Refuse payloads that standard relay policy would reject
+	dataSize := len(data) / 2
+	if dataSize > opReturnDataLimit {
+		log.Printf("OP_RETURN data of %d bytes exceeds the %d byte policy limit, refusing to create transaction", dataSize, opReturnDataLimit)
+		return "", nil
+	}
+
 	unlocked := UnlockWallet()
 	if !unlocked {
 		log.Printf("Failed to unlock wallet")
-		return ""
+		return "", nil
 	}
 
 	log.Printf("Wallet unlocked: %t", unlocked)
 
 	// Step 1: Get unspent outputs
-	unspent := ListUnspent()
-	if unspent == "" {
-		log.Printf("Failed to get unspent outputs")
-		return ""
+	if funding == nil {
+		unspent := ListUnspent()
+		if unspent == "" {
+			log.Printf("Failed to get unspent outputs")
+			return "", nil
+		}
+		utxos, err := ParseUTXOs(unspent)
+		if err != nil {
+			log.Printf("Failed to parse UTXOs: %v", err)
+			return "", nil
+		}
+		funding = utxos
 	}
 
-	// Step 2: Filter UTXOs
-	inputs, change, changeAddress := FilterUTXOs(unspent, len(data))
+	// Step 2: Select UTXOs against the current network fee rate
+	feeRate := CurrentFeeRate()
+	inputs, change, changeAddress, fee := selectInputs(strategy, funding, dataSize, feeRate)
 	if len(inputs) == 0 {
 		log.Printf("No suitable UTXOs found for transaction")
-		return ""
+		return "", nil
 	}
 
-	if changeAddress == "" {
-		log.Printf("No change address found in UTXOs")
-		return ""
+	// Steps 3-6: Create, decode and sign the transaction
+	signedHex := buildSignedOPReturn(inputs, changeAddress, change, data)
+	if signedHex == "" {
+		return "", nil
 	}
 
-	// Step 3: Create raw transaction using change address from UTXOs
+	// The selection used an estimated size; if the signed transaction is
+	// larger, take the shortfall out of the change and sign again
+	vsize, err := TxVsize(signedHex)
+	if err != nil {
+		log.Printf("Failed to measure signed transaction: %v", err)
+		return "", nil
+	}
+	needed := FeeForVsize(vsize, feeRate)
+	if float64(fee) < float64(needed)*feeRateTolerance {
+		if change == 0 {
+			log.Printf("Changeless anchor pays %d sats, below the %d sats needed for vsize %d", fee, needed, vsize)
+			return "", nil
+		}
+		changeSats := btcToSats(change) - (needed - fee)
+		if changeSats < dustLimitSats {
+			log.Printf("Change of %d sats after fee adjustment is below dust", changeSats)
+			return "", nil
+		}
+		log.Printf("Signed vsize %d exceeds estimate, raising fee from %d to %d sats", vsize, fee, needed)
+		change, fee = satsToBTC(changeSats), needed
+
+		signedHex = buildSignedOPReturn(inputs, changeAddress, change, data)
+		if signedHex == "" {
+			return "", nil
+		}
+	}
+	log.Printf("Anchor vsize %d vB, fee %d sats (%.2f sat/vB)", vsize, fee, float64(fee)/float64(vsize))
+
+	// Step 7: Send signed transaction
+	sendtscn := SendSignedTransaction(signedHex)
+	if sendtscn == "" {
+		log.Printf("Failed to send signed transaction")
+		return "", nil
+	}
+
+	log.Printf("Successfully created OP_RETURN transaction: %s", sendtscn)
+	return sendtscn, changeOutput(sendtscn, signedHex, changeAddress, change)
+}
+
+// changeOutput locates the change output of a signed anchor, or returns nil
+// if the anchor has none
+func changeOutput(txHash string, signedHex string, changeAddress string, change float64) *utxo {
+	if change == 0 {
+		return nil
+	}
+
+	raw, err := hex.DecodeString(signedHex)
+	if err != nil {
+		return nil
+	}
+	var tx wire.MsgTx
+	if err := tx.Deserialize(bytes.NewReader(raw)); err != nil {
+		return nil
+	}
+
+	for i, out := range tx.TxOut {
+		if out.Value == btcToSats(change) && txscript.GetScriptClass(out.PkScript) != txscript.NullDataTy {
+			return &utxo{
+				Txid:         txHash,
+				Vout:         i,
+				Amount:       change,
+				Address:      changeAddress,
+				ScriptPubKey: hex.EncodeToString(out.PkScript),
+			}
+		}
+	}
+	return nil
+}
+
+// buildSignedOPReturn creates and signs an anchor transaction, returning the signed hex
+func buildSignedOPReturn(inputs []map[string]interface{}, changeAddress string, change float64, data string) string {
 	rawtscn := CreateRawTransaction(inputs, changeAddress, change, data)
 	if rawtscn == "" {
 		log.Printf("Failed to create raw transaction")
 		return ""
 	}
 
-	// Step 4: Decode for verification (optional)
+	// Decode for verification (optional)
 	DecodeRawTransaction(rawtscn)
 
-	// Step 5: Sign transaction
 	signtscn := SignRawTransaction(rawtscn)
 	if signtscn == "" {
 		log.Printf("Failed to sign transaction")
 		return ""
 	}
 
-	// Step 6: Parse signed transaction
 	var sgn signedtx
 	err := json.Unmarshal([]byte(signtscn), &sgn)
 	if err != nil {
@@ -617,15 +721,7 @@ func CreateOPReturnTransaction(data string) string {
 		return ""
 	}
 
-	// Step 7: Send signed transaction
-	sendtscn := SendSignedTransaction(sgn.Hex)
-	if sendtscn == "" {
-		log.Printf("Failed to send signed transaction")
-		return ""
-	}
-
-	log.Printf("Successfully created OP_RETURN transaction: %s", sendtscn)
-	return sendtscn
+	return sgn.Hex
 }
 
 func InitOPReturnRPC(endpoint string, auth string, passphrase string) {
@@ -0,0 +1,668 @@
+package da
+
+import (
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Layer-Edge/bitcoin-da/clients"
+	"github.com/Layer-Edge/bitcoin-da/config"
+	"github.com/Layer-Edge/bitcoin-da/models"
+	"github.com/Layer-Edge/bitcoin-da/utils"
+)
+
+// Reasons a pending proof is discarded without being aggregated
+const (
+	DiscardedDuplicate = "duplicate" // another pending proof holds the same leaf
+	DiscardedRejected  = "rejected"  // the proof no longer passes the checks of its window
+)
+
+// pipelineItem is a submission on its way through the writer pipeline
+type pipelineItem struct {
+	namespace string
+	label     string
+	proofType string
+	proof     []byte
+	signature []byte
+	proofID   string
+
+	limitKey         string // rate limit key charged for the proof
+	claimed          bool   // the leaf is claimed in writerPipeline.claims
+	verification     string
+	verifyingKeyHash string
+
+	reply func(ack *Ack)
+}
+
+// writerPipeline runs HashBlockSubscriber as stages connected by bounded
+// channels: receive, validate, persist, seal and publish. Validation and
+// persistence run in worker pools, the open batch belongs to the seal
+// stage, and sealed batches are published one at a time in order, so new
+// proofs keep flowing into the next batch while one is stored on LayerEdge.
+type writerPipeline struct {
+	cfg         *config.Config
+	dataReader  *BlockSubscriber
+	validators  *ProofValidatorRegistry
+	verifier    *Groth16Verifier
+	snark       *SnarkAggregator
+	limiter     *RateLimiter
+	sealer      *Sealer
+	prf         ZKProof
+	writePeriod time.Duration
+
+	received  chan *pipelineItem
+	validated chan *pipelineItem
+	persisted chan *models.PendingProof
+	sealed    chan *Batch
+
+	// lastWrite is the aligned boundary the open batch started at
+	lastWrite atomic.Int64
+	// claims holds the leaves between validation and the seal stage, which
+	// concurrent workers cannot see in the database yet
+	claims sync.Map
+	// mined holds the chunks stored on LayerEdge but not yet recorded in the
+	// database, by merkle root. Only the publish stage uses it.
+	mined map[string]*minedChunk
+}
+
+// minedChunk is a chunk whose merkle root is stored on LayerEdge
+type minedChunk struct {
+	txData    *clients.TxData
+	aggregate *SnarkAggregate // set for the snark backend
+}
+
+func newWriterPipeline(cfg *config.Config, dataReader *BlockSubscriber) *writerPipeline {
+	p := &writerPipeline{
+		cfg:         cfg,
+		dataReader:  dataReader,
+		prf:         NewZKProof(cfg),
+		writePeriod: time.Duration(cfg.WriteIntervalSeconds) * time.Second,
+		received:    make(chan *pipelineItem, cfg.Pipeline.QueueSize),
+		validated:   make(chan *pipelineItem, cfg.Pipeline.QueueSize),
+		persisted:   make(chan *models.PendingProof, cfg.Pipeline.QueueSize),
+		sealed:      make(chan *Batch, cfg.Pipeline.PublishQueue),
+		mined:       make(map[string]*minedChunk),
+	}
+	// Start at the current aligned boundary so the first write occurs at the next one
+	p.lastWrite.Store(time.Now().Truncate(p.writePeriod).Unix())
+
+	// Gas does not depend on the root, so the native root stands in for the
+	// configured merkle backend when estimating
+	p.sealer = NewSealer(cfg, func(leaves []string) (uint64, error) {
+		root, err := NativeMerkleBackend{}.Root(leaves)
+		if err != nil {
+			return 0, err
+		}
+		return clients.EstimateStoreTreeGas(cfg, cfg.LayerEdgeRPC.MerkleTreeStorageContract, root, leaves)
+	})
+	return p
+}
+
+// Run starts every stage with the proofs restored from a previous run, and
+// blocks forever. Proofs of batches sealed before the restart are published
+// first, in their batches; the others go into the open batch.
+func (p *writerPipeline) Run(restored []models.PendingProof) {
+	sealed, open := p.restoreBatches(restored)
+
+	for i := 0; i < p.cfg.Pipeline.ValidateWorkers; i++ {
+		go p.validateStage()
+	}
+	for i := 0; i < p.cfg.Pipeline.PersistWorkers; i++ {
+		go p.persistStage()
+	}
+	go p.publishStage(sealed)
+	go p.receiveSubmissions()
+	go p.sealStage(open)
+
+	p.receiveDataSocket()
+}
+
+// restoreBatches groups the proofs restored from a previous run into the
+// batches they were sealed in, in sealing order, and the proofs of the open
+// batch. Proofs that cannot be aggregated are discarded, so they are not
+// restored again.
+func (p *writerPipeline) restoreBatches(restored []models.PendingProof) ([]*Batch, []*models.PendingProof) {
+	var open []*models.PendingProof
+	var order []string
+	byBatch := map[string][]*models.PendingProof{}
+	leaves := map[string]bool{}
+	var duplicates, rejected []int64
+
+	for i := range restored {
+		pending := &restored[i]
+		if leaves[pending.LeafHash] {
+			log.Printf("Discarding duplicate leaf %s (pending proof %d)", pending.LeafHash, pending.ID)
+			duplicates = append(duplicates, pending.ID)
+			continue
+		}
+		// Keys can change across a restart
+		if p.verifier != nil {
+			verification, keyHash, err := p.verifier.Verify(pending.Namespace, pending.Proof)
+			if err != nil {
+				log.Printf("Discarding pending proof %d, it no longer verifies: %v", pending.ID, err)
+				rejected = append(rejected, pending.ID)
+				continue
+			}
+			p.reverified(pending, verification, keyHash)
+		}
+		if p.snark != nil {
+			if err := p.snark.Validate(pending.Proof); err != nil {
+				log.Printf("Discarding pending proof %d, the snark backend cannot aggregate it: %v", pending.ID, err)
+				rejected = append(rejected, pending.ID)
+				continue
+			}
+		}
+		leaves[pending.LeafHash] = true
+		if pending.BatchID == nil {
+			open = append(open, pending)
+			continue
+		}
+		if _, ok := byBatch[*pending.BatchID]; !ok {
+			order = append(order, *pending.BatchID)
+		}
+		byBatch[*pending.BatchID] = append(byBatch[*pending.BatchID], pending)
+	}
+
+	p.discard(duplicates, DiscardedDuplicate)
+	p.discard(rejected, DiscardedRejected)
+
+	sealed := make([]*Batch, len(order))
+	for i, id := range order {
+		sealed[i] = RestoreSealedBatch(id, byBatch[id])
+	}
+	if len(sealed) > 0 {
+		log.Printf("Restored %d sealed batches that were not published", len(sealed))
+	}
+	return sealed, open
+}
+
+// reverified records the outcome of verifying a restored proof again when
+// it differs from the stored one, as after a key rotation
+func (p *writerPipeline) reverified(pending *models.PendingProof, verification string, keyHash string) {
+	stored := ""
+	if pending.VerifyingKeyHash != nil {
+		stored = *pending.VerifyingKeyHash
+	}
+	if pending.Verification == verification && stored == keyHash {
+		return
+	}
+	if err := models.UpdatePendingProofVerification(pending.ID, verification, keyHash); err != nil {
+		utils.LogDatabaseError("HashBlockSubscriber", "Failed to update proof verification", err, map[string]interface{}{
+			"pending_proof": pending.ID,
+		})
+	}
+}
+
+// discard consumes pending proofs that will never be aggregated. A failure
+// only leaves them to be discarded again after the next restart.
+func (p *writerPipeline) discard(ids []int64, reason string) {
+	if err := models.DiscardPendingProofs(ids, reason); err != nil {
+		utils.LogDatabaseError("HashBlockSubscriber", "Failed to discard pending proofs", err, map[string]interface{}{
+			"proofs": len(ids),
+			"reason": reason,
+		})
+	}
+}
+
+// expectedWindow is the window a proof accepted now will be sealed in,
+// unless a sealing limit is reached first
+func (p *writerPipeline) expectedWindow() *AckWindow {
+	start := time.Unix(p.lastWrite.Load(), 0).UTC()
+	if aligned := time.Now().Truncate(p.writePeriod); aligned.After(start) {
+		start = aligned.UTC()
+	}
+	return &AckWindow{Start: start, End: start.Add(p.writePeriod)}
+}
+
+// receiveDataSocket feeds data socket requests into the pipeline. The REP
+// socket answers every request before taking the next one, so it waits for
+// the ACK of each proof.
+func (p *writerPipeline) receiveDataSocket() {
+	for {
+		ok, msg := p.dataReader.GetMessage()
+		if !ok {
+			log.Println("Failed to receive message or channel closed")
+			time.Sleep(1 * time.Second) // Brief pause before retry
+			continue
+		}
+
+		if IsStatusQuery(msg) {
+			p.dataReader.SendStatus(string(msg[1]))
+			continue
+		}
+
+		log.Println("Received data for aggregation")
+		if err := p.dataReader.ValidateMessage(true, msg); err != nil {
+			log.Printf("Message validation failed, skipping: %v", err)
+			p.dataReader.SendAck(NewAck(AckStatusRejected, "", err.Error()))
+			continue
+		}
+		item := &pipelineItem{
+			namespace: NamespaceFromLabel(string(msg[0])),
+			label:     string(msg[0]),
+			proof:     msg[1],
+			signature: msg[2], // the third part carries the submitter's signature
+		}
+		if len(msg) == 4 {
+			item.proofType = string(msg[3])
+		}
+
+		acks := make(chan *Ack, 1)
+		item.reply = func(ack *Ack) { acks <- ack }
+		p.received <- item
+		p.dataReader.SendAck(<-acks)
+	}
+}
+
+// receiveSubmissions feeds submissions from the HTTP and gRPC APIs into the
+// pipeline
+func (p *writerPipeline) receiveSubmissions() {
+	for sub := range submissions {
+		log.Println("Received data for aggregation from the API")
+		reply := sub.reply
+		p.received <- &pipelineItem{
+			namespace: sub.Namespace,
+			label:     sub.Label,
+			proofType: sub.ProofType,
+			proof:     sub.Proof,
+			signature: sub.Signature,
+			reply:     func(ack *Ack) { reply <- ack },
+		}
+	}
+}
+
+// finish answers a submission, releasing what it holds unless it was accepted
+func (p *writerPipeline) finish(item *pipelineItem, ack *Ack) {
+	if ack.Status != AckStatusAccepted {
+		if item.limitKey != "" {
+			p.limiter.Release(item.limitKey)
+		}
+		if item.claimed {
+			p.claims.Delete(item.proofID)
+		}
+	}
+	item.reply(ack)
+}
+
+// recoverItem fails a submission whose stage panicked
+func (p *writerPipeline) recoverItem(stage string, item *pipelineItem) {
+	if r := recover(); r != nil {
+		log.Printf("Panic in %s stage: %v", stage, r)
+		p.finish(item, NewAck(AckStatusFailed, item.proofID, "internal error, please retry"))
+	}
+}
+
+func (p *writerPipeline) validateStage() {
+	for item := range p.received {
+		if ack := p.validate(item); ack != nil {
+			p.finish(item, ack)
+			continue
+		}
+		p.validated <- item
+	}
+}
+
+// validate authenticates, rate limits, deduplicates and checks a submission.
+// It returns nil when the proof can be persisted, and its ACK otherwise.
+func (p *writerPipeline) validate(item *pipelineItem) (ack *Ack) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Panic in validate stage: %v", r)
+			ack = NewAck(AckStatusFailed, item.proofID, "internal error, please retry")
+		}
+	}()
+
+	item.proofID = utils.Keccak256Hash(item.proof)
+
+	submitter, err := p.dataReader.Authenticate(item.namespace, item.proof, item.signature)
+	if err != nil {
+		return NewAck(AckStatusRejected, "", err.Error())
+	}
+
+	// Enforce the rate limits of the namespace or submitter
+	if p.limiter != nil {
+		key := item.namespace
+		if p.cfg.RateLimit.Key == "submitter" && submitter != nil {
+			key = submitter.Name
+		}
+		if retryAfter, err := p.limiter.Reserve(key, p.expectedWindow().End); err != nil {
+			log.Printf("Throttled submission: %v", err)
+			return NewThrottledAck(item.proofID, err.Error(), retryAfter)
+		}
+		item.limitKey = key
+	}
+
+	// Resubmissions point to the first one instead of adding the leaf again
+	if _, loaded := p.claims.LoadOrStore(item.proofID, true); loaded {
+		ack := NewAck(AckStatusDuplicate, item.proofID, "proof is already queued for aggregation")
+		ack.Window = p.expectedWindow()
+		return ack
+	}
+	item.claimed = true
+
+	existing, err := models.GetPendingProofByLeafHash(item.proofID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		utils.LogDatabaseError("HashBlockSubscriber", "Failed to look up leaf", err, nil)
+		return NewAck(AckStatusFailed, item.proofID, "failed to check for duplicates, please retry")
+	}
+	// A discarded submission does not hold the leaf, so the proof is checked again
+	if err == nil && existing.Discarded == "" {
+		return NewDuplicateAck(existing, p.expectedWindow())
+	}
+
+	// Check the payload before it can reach the window
+	if p.validators != nil {
+		if err := p.validators.Validate(item.proofType, item.proof); err != nil {
+			log.Printf("Proof validation failed: %v", err)
+			return NewAck(AckStatusRejected, item.proofID, err.Error())
+		}
+		item.proofType = p.validators.ResolveType(item.proofType)
+	}
+
+	// Only proofs that verified, or namespaces without a key when keys
+	// are optional, make it into a window
+	if p.verifier != nil {
+		item.verification, item.verifyingKeyHash, err = p.verifier.Verify(item.namespace, item.proof)
+		if err != nil {
+			log.Printf("Groth16 verification failed for %s in %s: %v", item.proofID, item.namespace, err)
+			return NewAck(AckStatusRejected, item.proofID, err.Error())
+		}
+	}
+	if p.snark != nil {
+		if err := p.snark.Validate(item.proof); err != nil {
+			log.Printf("Proof %s cannot be aggregated by the snark backend: %v", item.proofID, err)
+			return NewAck(AckStatusRejected, item.proofID, "proof cannot be aggregated: "+err.Error())
+		}
+	}
+	return nil
+}
+
+func (p *writerPipeline) persistStage() {
+	for item := range p.validated {
+		p.persist(item)
+	}
+}
+
+// persist records a validated proof and hands it to the seal stage before
+// acknowledging it, so an accepted proof survives a restart
+func (p *writerPipeline) persist(item *pipelineItem) {
+	defer p.recoverItem("persist", item)
+
+	pending, err := models.CreatePendingProof(item.namespace, item.label, item.proofType, item.proof, item.proofID, item.verification, item.verifyingKeyHash)
+	if err != nil {
+		utils.LogDatabaseError("HashBlockSubscriber", "Failed to persist received proof", err, nil)
+		p.finish(item, NewAck(AckStatusFailed, item.proofID, "failed to store proof, please retry"))
+		return
+	}
+
+	p.persisted <- pending
+	// The leaf is in the database now, where validation finds it
+	p.claims.Delete(item.proofID)
+
+	ack := NewAck(AckStatusAccepted, item.proofID, "")
+	ack.Window = p.expectedWindow()
+	p.finish(item, ack)
+}
+
+// sealStage owns the open batch: it appends persisted proofs to it and
+// seals it for the publish stage when the sealing policy says so
+func (p *writerPipeline) sealStage(restored []*models.PendingProof) {
+	open := NewBatch(time.Unix(p.lastWrite.Load(), 0))
+	deferred := false // sealing waits for room in the publish queue
+
+	fnAgg := func(pending *models.PendingProof) {
+		added, err := open.Append(pending)
+		if err != nil {
+			log.Printf("Failed to add pending proof %d to batch: %v", pending.ID, err)
+			return
+		}
+		if !added {
+			log.Printf("Discarding duplicate leaf %s (pending proof %d)", pending.LeafHash, pending.ID)
+			p.discard([]int64{pending.ID}, DiscardedDuplicate)
+			return
+		}
+		log.Println("Aggregating message: ", pending.Namespace, pending.Label, "proof length:", len(pending.Proof))
+	}
+
+	// Rebuild the open batch from proofs accepted before a restart
+	for _, pending := range restored {
+		fnAgg(pending)
+	}
+	if open.Len() > 0 {
+		log.Printf("Restored %d pending proofs into the open batch %s", open.Len(), open.ID)
+	}
+
+	checkAndSeal := func() {
+		nowTime := time.Now()
+		seal, reason := p.sealer.ShouldSeal(open.Stats(), nowTime)
+		if !seal {
+			return
+		}
+
+		if open.Len() > 0 {
+			// Only this stage sends to the publish queue, so room seen here
+			// is still there when the batch is sent
+			if len(p.sealed) == cap(p.sealed) {
+				if !deferred {
+					log.Printf("Publish queue is full, keeping batch %s of %d leaves open", open.ID, open.Len())
+					deferred = true
+				}
+				return
+			}
+			// Record the batch before sealing it, so a restart publishes it
+			// as sealed instead of reopening its proofs
+			if err := models.AssignPendingProofsToBatch(open.IDs(), open.ID); err != nil {
+				utils.LogDatabaseError("HashBlockSubscriber", "Failed to record batch, keeping it open", err, map[string]interface{}{
+					"batch":  open.ID,
+					"proofs": open.Len(),
+				})
+				return
+			}
+			if err := open.Seal(); err != nil {
+				log.Printf("Failed to seal batch: %v", err)
+				return
+			}
+			log.Printf("Sealed batch %s of %d leaves: %s", open.ID, open.Len(), reason)
+			p.sealed <- open
+			deferred = false
+		}
+
+		// Record the boundary the next batch starts at, so the interval
+		// policy seals once per aligned interval
+		p.lastWrite.Store(nowTime.Truncate(p.writePeriod).Unix())
+		open = NewBatch(time.Unix(p.lastWrite.Load(), 0))
+		if p.limiter != nil {
+			p.limiter.ResetWindow()
+		}
+	}
+
+	// Check every second so batches seal on time without new proofs
+	ticker := time.NewTicker(1 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case pending := <-p.persisted:
+			fnAgg(pending)
+		case <-ticker.C:
+		}
+		checkAndSeal()
+	}
+}
+
+// publishStage stores sealed batches on LayerEdge in the order they were
+// sealed, starting with the batches restored sealed from a previous run
+func (p *writerPipeline) publishStage(restored []*Batch) {
+	for _, batch := range restored {
+		p.publishBatch(batch)
+	}
+	for batch := range p.sealed {
+		p.publishBatch(batch)
+	}
+}
+
+// publishBatch publishes batch, retrying from its first unstored leaf at
+// each following interval while later batches wait in the publish queue.
+// After pipeline.publish-attempts failures the batch is set aside so later
+// batches are published; its proofs stay sealed and it is retried first on
+// the next start.
+func (p *writerPipeline) publishBatch(batch *Batch) {
+	for !p.publish(batch) {
+		attempts := batch.Attempt()
+		if attempts >= p.cfg.Pipeline.PublishAttempts {
+			utils.LogSystemError("HashBlockSubscriber", "Setting batch aside until the next start", fmt.Errorf("publishing failed %d times", attempts), map[string]interface{}{
+				"batch":  batch.ID,
+				"proofs": batch.Len(),
+			})
+			return
+		}
+		next := time.Now().Truncate(p.writePeriod).Add(p.writePeriod)
+		log.Printf("Keeping %d proofs of batch %s sealed after %d attempts, retrying at %s",
+			batch.Len(), batch.ID, attempts, next.Format(time.RFC3339))
+		time.Sleep(time.Until(next))
+	}
+	log.Printf("Published batch %s", batch.ID)
+}
+
+// publish stores a sealed batch, split into as many transactions as the
+// sealing limits require, marking stored leaves in it. It reports whether
+// every leaf was stored.
+func (p *writerPipeline) publish(batch *Batch) (stored bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Panic in publish stage: %v", r)
+			stored = false
+		}
+	}()
+
+	proofs := batch.Proofs()
+	chunks := p.sealer.Split(batch.Stats())
+	if len(chunks) > 1 {
+		log.Printf("Splitting %d leaves of batch %s into %d StoreMerkleTree transactions", len(proofs), batch.ID, len(chunks))
+	}
+	for _, n := range chunks {
+		if !p.storeChunk(proofs[:n]) {
+			return false
+		}
+		if err := batch.MarkStored(n); err != nil {
+			log.Printf("Failed to mark proofs stored: %v", err)
+			return false
+		}
+		proofs = proofs[n:]
+	}
+	return true
+}
+
+// storeChunk aggregates and stores proofs in one StoreMerkleTree transaction.
+// On any failure they stay pending. A root that is already on LayerEdge is
+// not stored again: only its database write is retried.
+func (p *writerPipeline) storeChunk(proofs []*models.PendingProof) bool {
+	leaves := make([]string, len(proofs))
+	proof_list := make([]string, len(proofs)) // hex-encoded proofs for database storage
+	pending_ids := make([]int64, len(proofs))
+	for i, pending := range proofs {
+		leaves[i] = pending.LeafHash
+		proof_list[i] = "0x" + hex.EncodeToString(pending.Proof)
+		pending_ids[i] = pending.ID
+	}
+
+	// Generate and process proof
+	merkle_root := p.prf.GenerateAggregatedProof(leaves)
+	if merkle_root == "" {
+		log.Println("Failed to generate aggregated proof, skipping write")
+		return false
+	}
+
+	log.Println("Aggregated Data: ", strings.Join(leaves, ","))
+	log.Println("Aggregated Proof: ", merkle_root)
+
+	chunk, err := p.minedChunk(merkle_root)
+	if err != nil {
+		log.Printf("Failed to check whether %s is stored, keeping %d proofs pending: %v", merkle_root, len(proofs), err)
+		return false
+	}
+	if chunk != nil {
+		log.Printf("Merkle root %s is already stored in %s, retrying its database write", merkle_root, chunk.txData.TransactionHash)
+	}
+
+	// Prove before storing the root so a failed proof costs no transaction
+	if p.snark != nil && (chunk == nil || chunk.aggregate == nil) {
+		payloads := make([][]byte, len(proofs))
+		for i, pending := range proofs {
+			payloads[i] = pending.Proof
+		}
+		snarkAggregate, err := p.snark.Aggregate(payloads)
+		if err != nil {
+			log.Printf("Failed to generate SNARK aggregate, keeping %d proofs pending: %v", len(proofs), err)
+			return false
+		}
+		if chunk != nil {
+			chunk.aggregate = snarkAggregate
+		} else {
+			chunk = &minedChunk{aggregate: snarkAggregate}
+		}
+	}
+
+	if chunk == nil || chunk.txData == nil {
+		// Store merkle tree with retry mechanism
+		txData, err := clients.StoreMerkleTree(p.cfg, p.cfg.LayerEdgeRPC.MerkleTreeStorageContract, merkle_root, leaves)
+		if err != nil {
+			log.Printf("Error storing merkle tree: %v", err)
+		}
+		if txData == nil {
+			log.Printf("No transaction data available, keeping %d proofs pending", len(proofs))
+			return false
+		}
+		if chunk == nil {
+			chunk = &minedChunk{}
+		}
+		chunk.txData = txData
+		p.mined[merkle_root] = chunk
+	}
+	txData, aggregate := chunk.txData, chunk.aggregate
+
+	var aggProof sql.Result
+	if aggregate != nil {
+		aggProof, err = models.CreateSnarkAggregatedProof(merkle_root, aggregate.Proof, aggregate.PublicInputs, proof_list, pending_ids, *txData)
+	} else {
+		aggProof, err = models.CreateAggregatedProof(merkle_root, proof_list, pending_ids, *txData)
+	}
+	if err != nil {
+		log.Printf("Failed to store Aggregated Proof in DB, keeping %d proofs pending: %v", len(proofs), err)
+		return false
+	}
+	log.Printf("Stored Aggregated Proof and consumed %d pending proofs: %v", len(proofs), aggProof)
+	delete(p.mined, merkle_root)
+	return true
+}
+
+// minedChunk returns the chunk stored on LayerEdge under merkle_root, or nil
+// if the root is not stored yet. Roots stored before a restart, or by a call
+// that failed after its transaction was mined, are found on chain.
+func (p *writerPipeline) minedChunk(merkle_root string) (*minedChunk, error) {
+	if chunk, ok := p.mined[merkle_root]; ok {
+		return chunk, nil
+	}
+
+	exists, err := clients.TreeExists(p.cfg, p.cfg.LayerEdgeRPC.MerkleTreeStorageContract, merkle_root)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, nil
+	}
+
+	txData, err := clients.StoredTreeTx(p.cfg, p.cfg.LayerEdgeRPC.MerkleTreeStorageContract, merkle_root)
+	if err != nil {
+		return nil, fmt.Errorf("root is stored but its transaction was not found: %w", err)
+	}
+	chunk := &minedChunk{txData: txData}
+	p.mined[merkle_root] = chunk
+	return chunk, nil
+}
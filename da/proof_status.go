@@ -0,0 +1,161 @@
+package da
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Layer-Edge/bitcoin-da/merkle"
+	"github.com/Layer-Edge/bitcoin-da/models"
+)
+
+// StatusQueryLabel marks a data socket request as a status query. The second
+// and last frame carries the leaf hash (proof ID) to look up. Submissions
+// cannot use it as their label.
+const StatusQueryLabel = "status"
+
+// Lifecycle stages of a submitted proof, in order
+const (
+	ProofStageUnknown      = "unknown"
+	ProofStagePending      = "pending"        // persisted, waiting for its window
+	ProofStageAggregated   = "aggregated"     // sealed into an aggregate whose LayerEdge tx did not succeed
+	ProofStageStored       = "stored"         // aggregate stored on LayerEdge
+	ProofStageInSuperProof = "in-super-proof" // aggregate rolled into a super proof awaiting anchoring
+	ProofStageAnchored     = "anchored"       // super proof anchored on Bitcoin
+	ProofStageConfirmed    = "confirmed"      // anchor reached the required confirmations
+	ProofStageRejected     = "rejected"       // discarded without being aggregated
+)
+
+// ErrInvalidProofID means a status query's leaf hash could not be parsed
+var ErrInvalidProofID = errors.New("invalid proof id")
+
+// ProofStatus is the reply to a status query
+type ProofStatus struct {
+	Version int    `json:"version"`
+	ProofID string `json:"proof_id"`
+	Stage   string `json:"stage"`
+
+	Namespace    string `json:"namespace,omitempty"`
+	LeafIndex    *int   `json:"leaf_index,omitempty"`   // position in the aggregate
+	Verification string `json:"verification,omitempty"` // Groth16 verification outcome
+
+	ReceivedAt       *time.Time `json:"received_at,omitempty"`
+	AggregateRoot    string     `json:"aggregate_root,omitempty"`
+	AggregateTxHash  string     `json:"aggregate_tx_hash,omitempty"`
+	SuperProofRoot   string     `json:"super_proof_root,omitempty"`
+	SuperProofTxHash string     `json:"super_proof_tx_hash,omitempty"`
+	BTCTxHash        string     `json:"btc_tx_hash,omitempty"`
+	BTCBlockNumber   *int64     `json:"btc_block_number,omitempty"`
+	BTCConfirmations int64      `json:"btc_confirmations,omitempty"`
+
+	InclusionProof *AnchoredInclusionProof `json:"inclusion_proof,omitempty"`
+	Error          string                  `json:"error,omitempty"`
+}
+
+// IsStatusQuery reports whether a data socket request is a status query
+func IsStatusQuery(msg [][]byte) bool {
+	return len(msg) == 2 && string(msg[0]) == StatusQueryLabel
+}
+
+// GetProofStatus looks up how far the proof with the given leaf hash has
+// progressed and, once it is aggregated, its inclusion proof. It fails with
+// ErrInvalidProofID if leaf is not a hash; other errors come from the lookups.
+func GetProofStatus(leaf string) (*ProofStatus, error) {
+	leafHash, err := merkle.ParseLeaf(leaf)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidProofID, err)
+	}
+
+	status := &ProofStatus{
+		Version: AckVersion,
+		ProofID: leafHash.Hex(),
+		Stage:   ProofStageUnknown,
+	}
+
+	pending, err := models.GetPendingProofByLeafHash(status.ProofID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return status, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	status.Namespace = pending.Namespace
+	status.Verification = pending.Verification
+	status.ReceivedAt = &pending.ReceivedAt
+	status.Stage = ProofStagePending
+	if pending.Discarded != "" {
+		status.Stage = ProofStageRejected
+		status.Error = "proof was discarded: " + pending.Discarded
+		return status, nil
+	}
+	if pending.ConsumedAt == nil || pending.AggregateRoot == nil {
+		return status, nil
+	}
+
+	status.Stage = ProofStageAggregated
+	status.AggregateRoot = *pending.AggregateRoot
+	status.LeafIndex = pending.LeafIndex
+
+	aggregate, err := models.GetAggregatedProofByRoot(status.AggregateRoot)
+	if errors.Is(err, sql.ErrNoRows) {
+		return status, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	status.AggregateTxHash = aggregate.TransactionHash
+	if aggregate.Success {
+		status.Stage = ProofStageStored
+	}
+
+	proof, err := BuildAnchoredInclusionProof(aggregate, status.ProofID)
+	if err != nil {
+		// The stage is still meaningful without the path
+		status.Error = err.Error()
+		return status, nil
+	}
+	status.InclusionProof = proof
+
+	superProof, err := models.GetSuperProofContainingRoot(status.AggregateRoot)
+	if errors.Is(err, sql.ErrNoRows) {
+		return status, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	status.Stage = ProofStageInSuperProof
+	status.SuperProofRoot = superProof.MerkleRoot
+	status.SuperProofTxHash = superProof.TransactionHash
+	if superProof.BTCTxHash != nil && *superProof.BTCTxHash != "" {
+		status.Stage = ProofStageAnchored
+		status.BTCTxHash = *superProof.BTCTxHash
+		status.BTCBlockNumber = superProof.BTCBlockNumber
+		status.BTCConfirmations = superProof.BTCConfirmations
+		if superProof.BTCConfirmedAt != nil {
+			status.Stage = ProofStageConfirmed
+		}
+	}
+
+	return status, nil
+}
+
+// SendStatus answers a status query on the data socket. Lookup failures are
+// reported in the Error field with the stage left unknown.
+func (subr *BlockSubscriber) SendStatus(leaf string) {
+	status, err := GetProofStatus(leaf)
+	if err != nil {
+		log.Printf("Failed to look up status of proof %s: %v", leaf, err)
+		status = &ProofStatus{
+			Version: AckVersion,
+			ProofID: leaf,
+			Stage:   ProofStageUnknown,
+			Error:   err.Error(),
+		}
+	}
+	subr.sendReply("status", status)
+}
@@ -0,0 +1,39 @@
+package da
+
+import "testing"
+
+func TestStatusQueryFrames(t *testing.T) {
+	frames := func(parts ...string) [][]byte {
+		msg := make([][]byte, len(parts))
+		for i, part := range parts {
+			msg[i] = []byte(part)
+		}
+		return msg
+	}
+
+	tests := []struct {
+		name   string
+		msg    [][]byte
+		query  bool
+		submit bool // ValidateMessage accepts it as a submission
+	}{
+		{name: "status query", msg: frames(StatusQueryLabel, "0xleaf"), query: true},
+		{name: "submission", msg: frames("chain", "proof", "sig"), submit: true},
+		{name: "submission with a proof type", msg: frames("chain", "proof", "sig", "groth16"), submit: true},
+		{name: "submission labelled status", msg: frames(StatusQueryLabel, "proof", "sig")},
+		{name: "typed submission labelled status", msg: frames(StatusQueryLabel, "proof", "sig", "groth16")},
+		{name: "status label alone", msg: frames(StatusQueryLabel)},
+		{name: "two frames of another label", msg: frames("chain", "proof")},
+	}
+	subr := &BlockSubscriber{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsStatusQuery(tt.msg); got != tt.query {
+				t.Errorf("IsStatusQuery = %v, want %v", got, tt.query)
+			}
+			if err := subr.ValidateMessage(true, tt.msg); (err == nil) != tt.submit {
+				t.Errorf("ValidateMessage = %v, want accepted %v", err, tt.submit)
+			}
+		})
+	}
+}
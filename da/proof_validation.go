@@ -0,0 +1,255 @@
+package da
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Layer-Edge/bitcoin-da/config"
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+// ProofTypeStateRoot is always registered: a raw 32 byte state root
+const ProofTypeStateRoot = "state-root"
+
+// ProofValidator checks the payload of a submitted proof
+type ProofValidator interface {
+	Validate(proof []byte) error
+}
+
+// ProofValidatorFunc adapts a function to ProofValidator
+type ProofValidatorFunc func(proof []byte) error
+
+func (f ProofValidatorFunc) Validate(proof []byte) error {
+	return f(proof)
+}
+
+// ProofValidatorRegistry holds the validators of each proof type
+type ProofValidatorRegistry struct {
+	validators  map[string]ProofValidator
+	defaultType string
+}
+
+// NewProofValidatorRegistry registers the built-in validators and the proof
+// types from the config
+func NewProofValidatorRegistry(cfg *config.Config) (*ProofValidatorRegistry, error) {
+	r := &ProofValidatorRegistry{
+		validators:  map[string]ProofValidator{},
+		defaultType: cfg.ProofValidation.DefaultType,
+	}
+	r.Register(ProofTypeStateRoot, ProofValidatorFunc(validateStateRoot))
+
+	for _, proofType := range cfg.ProofValidation.Types {
+		validator, err := newProofValidator(proofType)
+		if err != nil {
+			return nil, fmt.Errorf("proof type %q: %w", proofType.Name, err)
+		}
+		r.Register(proofType.Name, validator)
+	}
+
+	if r.defaultType != "" && r.validators[r.defaultType] == nil {
+		return nil, fmt.Errorf("default proof type %q is not registered", r.defaultType)
+	}
+	return r, nil
+}
+
+func newProofValidator(proofType config.ProofType) (ProofValidator, error) {
+	switch proofType.Kind {
+	case "abi":
+		return NewABIValidator(proofType.ABI)
+	case "groth16", "plonk":
+		return NewGnarkValidator(proofType.Kind, proofType.Curve, proofType.VerifyingKey)
+	case ProofTypeStateRoot:
+		return ProofValidatorFunc(validateStateRoot), nil
+	default:
+		return nil, fmt.Errorf("unknown kind %q", proofType.Kind)
+	}
+}
+
+// Register adds or replaces the validator of proofType
+func (r *ProofValidatorRegistry) Register(proofType string, validator ProofValidator) {
+	r.validators[proofType] = validator
+}
+
+// ResolveType returns the proof type used for a submission naming proofType
+func (r *ProofValidatorRegistry) ResolveType(proofType string) string {
+	if proofType == "" {
+		return r.defaultType
+	}
+	return proofType
+}
+
+// Validate checks proof with the validator of proofType
+func (r *ProofValidatorRegistry) Validate(proofType string, proof []byte) error {
+	proofType = r.ResolveType(proofType)
+	if proofType == "" {
+		return fmt.Errorf("proof type is required")
+	}
+
+	validator, ok := r.validators[proofType]
+	if !ok {
+		return fmt.Errorf("unknown proof type %q", proofType)
+	}
+	if err := validator.Validate(proof); err != nil {
+		return fmt.Errorf("invalid %s proof: %w", proofType, err)
+	}
+	return nil
+}
+
+func validateStateRoot(proof []byte) error {
+	if len(proof) != 32 {
+		return fmt.Errorf("state root has %d bytes, expected 32", len(proof))
+	}
+	return nil
+}
+
+// ABIValidator accepts proofs that are exactly the ABI encoding of its arguments
+type ABIValidator struct {
+	arguments abi.Arguments
+}
+
+// NewABIValidator parses a JSON argument list such as
+//
+//	[{"name":"root","type":"bytes32"},{"name":"proof","type":"bytes"}]
+func NewABIValidator(arguments string) (*ABIValidator, error) {
+	if strings.TrimSpace(arguments) == "" {
+		return nil, fmt.Errorf("abi is required")
+	}
+
+	// abi.JSON only parses whole contract ABIs, so wrap the arguments as the
+	// inputs of a function
+	parsed, err := abi.JSON(strings.NewReader(fmt.Sprintf(`[{"type":"function","name":"proof","inputs":%s}]`, arguments)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid abi: %w", err)
+	}
+	return &ABIValidator{arguments: parsed.Methods["proof"].Inputs}, nil
+}
+
+func (v *ABIValidator) Validate(proof []byte) error {
+	values, err := v.arguments.Unpack(proof)
+	if err != nil {
+		return fmt.Errorf("abi decoding failed: %w", err)
+	}
+
+	// Unpack ignores trailing bytes, re-encoding does not
+	encoded, err := v.arguments.Pack(values...)
+	if err != nil {
+		return fmt.Errorf("abi encoding failed: %w", err)
+	}
+	if !bytes.Equal(encoded, proof) {
+		return fmt.Errorf("proof is not in canonical abi encoding")
+	}
+	return nil
+}
+
+// GnarkValidator verifies gnark Groth16 or PLONK proofs against a verifying
+// key. A proof payload is the binary proof followed by the binary public
+// witness, as written by their WriteTo and MarshalBinary methods.
+type GnarkValidator struct {
+	system string
+	curve  ecc.ID
+
+	groth16Key groth16.VerifyingKey
+	plonkKey   plonk.VerifyingKey
+}
+
+// NewGnarkValidator loads the verifying key of a groth16 or plonk circuit
+func NewGnarkValidator(system string, curve string, verifyingKeyPath string) (*GnarkValidator, error) {
+	curveID, err := ecc.IDFromString(curve)
+	if err != nil {
+		return nil, fmt.Errorf("curve %q: %w", curve, err)
+	}
+
+	data, err := os.ReadFile(verifyingKeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read verifying key: %w", err)
+	}
+
+	v, err := parseGnarkValidator(system, curveID, data)
+	if err != nil {
+		return nil, fmt.Errorf("verifying key %s: %w", verifyingKeyPath, err)
+	}
+	return v, nil
+}
+
+// parseGnarkValidator decodes a binary verifying key
+func parseGnarkValidator(system string, curve ecc.ID, verifyingKey []byte) (*GnarkValidator, error) {
+	var err error
+	v := &GnarkValidator{system: system, curve: curve}
+	switch system {
+	case "groth16":
+		v.groth16Key = groth16.NewVerifyingKey(curve)
+		_, err = v.groth16Key.ReadFrom(bytes.NewReader(verifyingKey))
+	case "plonk":
+		v.plonkKey = plonk.NewVerifyingKey(curve)
+		_, err = v.plonkKey.ReadFrom(bytes.NewReader(verifyingKey))
+	default:
+		return nil, fmt.Errorf("unknown proof system %q", system)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s verifying key: %w", system, err)
+	}
+	return v, nil
+}
+
+func (v *GnarkValidator) Validate(proof []byte) error {
+	var err error
+	if v.system == "groth16" {
+		groth16Proof, publicWitness, decodeErr := decodeGroth16Payload(v.curve, proof)
+		if decodeErr != nil {
+			return decodeErr
+		}
+		err = groth16.Verify(groth16Proof, v.groth16Key, publicWitness)
+	} else {
+		reader := bytes.NewReader(proof)
+		plonkProof := plonk.NewProof(v.curve)
+		if _, err := plonkProof.ReadFrom(reader); err != nil {
+			return fmt.Errorf("failed to decode plonk proof: %w", err)
+		}
+		publicWitness, decodeErr := readPublicWitness(v.curve, reader)
+		if decodeErr != nil {
+			return decodeErr
+		}
+		err = plonk.Verify(plonkProof, v.plonkKey, publicWitness)
+	}
+	if err != nil {
+		return fmt.Errorf("%s verification failed: %w", v.system, err)
+	}
+	return nil
+}
+
+// decodeGroth16Payload splits a groth16 proof payload into the proof and its
+// public witness
+func decodeGroth16Payload(curve ecc.ID, payload []byte) (groth16.Proof, witness.Witness, error) {
+	reader := bytes.NewReader(payload)
+	proof := groth16.NewProof(curve)
+	if _, err := proof.ReadFrom(reader); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode groth16 proof: %w", err)
+	}
+
+	publicWitness, err := readPublicWitness(curve, reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	return proof, publicWitness, nil
+}
+
+// readPublicWitness reads the public witness that ends a proof payload
+func readPublicWitness(curve ecc.ID, reader *bytes.Reader) (witness.Witness, error) {
+	publicWitness, err := witness.New(curve.ScalarField())
+	if err != nil {
+		return nil, err
+	}
+	if _, err := publicWitness.ReadFrom(reader); err != nil {
+		return nil, fmt.Errorf("failed to decode public witness: %w", err)
+	}
+	if reader.Len() != 0 {
+		return nil, fmt.Errorf("%d unexpected bytes after the public witness", reader.Len())
+	}
+	return publicWitness, nil
+}
@@ -0,0 +1,110 @@
+package da
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/Layer-Edge/bitcoin-da/config"
+)
+
+// RateLimiter enforces a token-bucket rate and a per-window leaf quota for
+// each namespace or submitter
+type RateLimiter struct {
+	defaultRule config.RateLimitRule
+	overrides   map[string]config.RateLimitRule
+
+	buckets map[string]*tokenBucket
+	window  map[string]int // leaves reserved in the open window
+	mu      sync.Mutex
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter creates a limiter with the rate-limit rules from the config
+func NewRateLimiter(cfg *config.Config) *RateLimiter {
+	l := &RateLimiter{
+		defaultRule: cfg.RateLimit.Default,
+		overrides:   map[string]config.RateLimitRule{},
+		buckets:     map[string]*tokenBucket{},
+		window:      map[string]int{},
+	}
+	for _, rule := range cfg.RateLimit.Overrides {
+		l.overrides[rule.Name] = rule
+	}
+	return l
+}
+
+func (l *RateLimiter) rule(key string) config.RateLimitRule {
+	if rule, ok := l.overrides[key]; ok {
+		return rule
+	}
+	return l.defaultRule
+}
+
+// Reserve takes a token and a leaf of the window quota for key. When key is
+// over either limit nothing is taken, and the returned duration is how long
+// the submitter should wait; windowEnd is when the quota frees up.
+func (l *RateLimiter) Reserve(key string, windowEnd time.Time) (time.Duration, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	rule := l.rule(key)
+	now := time.Now()
+
+	if rule.WindowQuota > 0 && l.window[key] >= rule.WindowQuota {
+		return windowEnd.Sub(now), fmt.Errorf("%s reached its quota of %d proofs for this window", key, rule.WindowQuota)
+	}
+
+	if rule.Rate > 0 {
+		burst := math.Max(float64(rule.Burst), 1)
+		bucket, ok := l.buckets[key]
+		if !ok {
+			bucket = &tokenBucket{tokens: burst, last: now}
+			l.buckets[key] = bucket
+		}
+		bucket.tokens = math.Min(burst, bucket.tokens+now.Sub(bucket.last).Seconds()*rule.Rate)
+		bucket.last = now
+
+		if bucket.tokens < 1 {
+			wait := time.Duration((1 - bucket.tokens) / rule.Rate * float64(time.Second))
+			return wait, fmt.Errorf("%s exceeded its rate limit of %g proofs per second", key, rule.Rate)
+		}
+		bucket.tokens--
+	}
+
+	l.window[key]++
+	return 0, nil
+}
+
+// Release returns the window quota taken for a proof that was not accepted.
+// The token stays spent.
+func (l *RateLimiter) Release(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.window[key] > 0 {
+		l.window[key]--
+	}
+}
+
+// ResetWindow starts the quotas of a new aggregation window and drops the
+// buckets that have refilled, so idle keys do not accumulate
+func (l *RateLimiter) ResetWindow() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.window = map[string]int{}
+
+	now := time.Now()
+	for key, bucket := range l.buckets {
+		rule := l.rule(key)
+		if rule.Rate <= 0 || bucket.tokens+now.Sub(bucket.last).Seconds()*rule.Rate >= math.Max(float64(rule.Burst), 1) {
+			delete(l.buckets, key)
+		}
+	}
+}
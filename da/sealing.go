@@ -0,0 +1,223 @@
+package da
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Layer-Edge/bitcoin-da/config"
+)
+
+// BatchStats describes the open aggregation window
+type BatchStats struct {
+	ID     string
+	Leaves []string
+	Sizes  []int     // proof bytes of each leaf
+	Start  time.Time // aligned boundary the window opened at
+}
+
+// Bytes is the total proof size of the batch
+func (b BatchStats) Bytes() int {
+	total := 0
+	for _, size := range b.Sizes {
+		total += size
+	}
+	return total
+}
+
+// SealPolicy decides when the open batch is sealed and stored
+type SealPolicy interface {
+	// ShouldSeal reports whether the batch must be sealed now, and why
+	ShouldSeal(batch BatchStats, now time.Time) (bool, string)
+}
+
+// GasEstimator estimates the storeTree gas of a list of leaves
+type GasEstimator func(leaves []string) (uint64, error)
+
+// AnyPolicy seals as soon as one of its policies does
+type AnyPolicy []SealPolicy
+
+func (p AnyPolicy) ShouldSeal(batch BatchStats, now time.Time) (bool, string) {
+	for _, policy := range p {
+		if seal, reason := policy.ShouldSeal(batch, now); seal {
+			return true, reason
+		}
+	}
+	return false, ""
+}
+
+// IntervalPolicy seals at every aligned write interval boundary
+type IntervalPolicy struct {
+	Period time.Duration
+}
+
+func (p IntervalPolicy) ShouldSeal(batch BatchStats, now time.Time) (bool, string) {
+	if now.Truncate(p.Period).Unix() > batch.Start.Unix() {
+		return true, fmt.Sprintf("write interval of %s elapsed", p.Period)
+	}
+	return false, ""
+}
+
+// LeafCountPolicy seals once the batch holds MaxLeaves leaves
+type LeafCountPolicy struct {
+	MaxLeaves int
+}
+
+func (p LeafCountPolicy) ShouldSeal(batch BatchStats, now time.Time) (bool, string) {
+	if p.MaxLeaves > 0 && len(batch.Leaves) >= p.MaxLeaves {
+		return true, fmt.Sprintf("%d leaves reached max-leaves %d", len(batch.Leaves), p.MaxLeaves)
+	}
+	return false, ""
+}
+
+// ByteSizePolicy seals once the proofs of the batch add up to MaxBytes
+type ByteSizePolicy struct {
+	MaxBytes int
+}
+
+func (p ByteSizePolicy) ShouldSeal(batch BatchStats, now time.Time) (bool, string) {
+	if p.MaxBytes > 0 && batch.Bytes() >= p.MaxBytes {
+		return true, fmt.Sprintf("%d proof bytes reached max-bytes %d", batch.Bytes(), p.MaxBytes)
+	}
+	return false, ""
+}
+
+// GasPolicy seals once storing the batch is estimated to cost MaxGas. Gas is
+// estimated in the background every CheckEvery leaves, so a slow RPC does not
+// hold up the seal stage; the batch is sealed once an estimate reaches MaxGas.
+// Failed estimates leave sealing to the other policies, and splitting catches
+// the leaves added while an estimate was running.
+type GasPolicy struct {
+	MaxGas     uint64
+	CheckEvery int
+	Estimate   GasEstimator
+
+	batch    string      // batch checked counts the leaves of
+	checked  int         // batch size at the last estimate started
+	running  bool        // an estimate is in flight
+	last     gasEstimate // last finished estimate
+	finished chan gasEstimate
+}
+
+// gasEstimate is the outcome of estimating the first leaves of a batch
+type gasEstimate struct {
+	batch  string
+	leaves int
+	gas    uint64
+	err    error
+}
+
+func (p *GasPolicy) ShouldSeal(batch BatchStats, now time.Time) (bool, string) {
+	if p.MaxGas == 0 || len(batch.Leaves) == 0 {
+		return false, ""
+	}
+	if p.finished == nil {
+		p.finished = make(chan gasEstimate, 1)
+	}
+	if batch.ID != p.batch {
+		p.batch, p.checked = batch.ID, 0
+	}
+
+	select {
+	case result := <-p.finished:
+		p.running = false
+		if result.err != nil {
+			log.Printf("Failed to estimate storeTree gas for %d leaves: %v", result.leaves, result.err)
+		} else {
+			p.last = result
+		}
+	default:
+	}
+
+	// The batch only grows, so an estimate of its first leaves still holds
+	if p.last.batch == batch.ID && p.last.gas >= p.MaxGas {
+		return true, fmt.Sprintf("estimated gas %d for %d leaves reached max-gas %d", p.last.gas, p.last.leaves, p.MaxGas)
+	}
+
+	if !p.running && len(batch.Leaves)-p.checked >= p.CheckEvery {
+		p.checked = len(batch.Leaves)
+		p.running = true
+		leaves := append([]string(nil), batch.Leaves...)
+		go func() {
+			gas, err := p.Estimate(leaves)
+			p.finished <- gasEstimate{batch: batch.ID, leaves: len(leaves), gas: gas, err: err}
+		}()
+	}
+	return false, ""
+}
+
+// Sealer applies the sealing config: when to seal the open batch, and how to
+// split a sealed batch that is over the limits into several transactions
+type Sealer struct {
+	Policy SealPolicy
+
+	maxLeaves int
+	maxBytes  int
+	maxGas    uint64
+	estimate  GasEstimator
+}
+
+// NewSealer builds the sealing policy from the config
+func NewSealer(cfg *config.Config, estimate GasEstimator) *Sealer {
+	s := &Sealer{
+		maxLeaves: cfg.Sealing.MaxLeaves,
+		maxBytes:  cfg.Sealing.MaxBytes,
+		maxGas:    cfg.Sealing.MaxGas,
+		estimate:  estimate,
+	}
+	s.Policy = AnyPolicy{
+		IntervalPolicy{Period: time.Duration(cfg.WriteIntervalSeconds) * time.Second},
+		LeafCountPolicy{MaxLeaves: s.maxLeaves},
+		ByteSizePolicy{MaxBytes: s.maxBytes},
+		&GasPolicy{MaxGas: s.maxGas, CheckEvery: cfg.Sealing.GasCheckEvery, Estimate: estimate},
+	}
+	return s
+}
+
+// ShouldSeal reports whether the open batch must be sealed now, and why
+func (s *Sealer) ShouldSeal(batch BatchStats, now time.Time) (bool, string) {
+	return s.Policy.ShouldSeal(batch, now)
+}
+
+// Split cuts a sealed batch into consecutive chunks that each fit max-leaves,
+// max-bytes and max-gas, and returns the number of leaves in each chunk. A
+// single leaf is never split further.
+func (s *Sealer) Split(batch BatchStats) []int {
+	var chunks []int
+	start := 0
+	for start < len(batch.Leaves) {
+		end, bytes := start, 0
+		for end < len(batch.Leaves) {
+			if s.maxLeaves > 0 && end-start >= s.maxLeaves {
+				break
+			}
+			if s.maxBytes > 0 && end > start && bytes+batch.Sizes[end] > s.maxBytes {
+				break
+			}
+			bytes += batch.Sizes[end]
+			end++
+		}
+		chunks = append(chunks, s.splitByGas(batch.Leaves[start:end])...)
+		start = end
+	}
+	return chunks
+}
+
+// splitByGas halves leaves until every part is estimated under max-gas
+func (s *Sealer) splitByGas(leaves []string) []int {
+	if s.maxGas == 0 || len(leaves) <= 1 {
+		return []int{len(leaves)}
+	}
+
+	gas, err := s.estimate(leaves)
+	if err != nil {
+		log.Printf("Failed to estimate storeTree gas for %d leaves, not splitting: %v", len(leaves), err)
+		return []int{len(leaves)}
+	}
+	if gas <= s.maxGas {
+		return []int{len(leaves)}
+	}
+
+	half := len(leaves) / 2
+	return append(s.splitByGas(leaves[:half]), s.splitByGas(leaves[half:])...)
+}
@@ -0,0 +1,195 @@
+package da
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// gasPerLeaf estimates 100 gas for every leaf
+func gasPerLeaf(leaves []string) (uint64, error) {
+	return uint64(100 * len(leaves)), nil
+}
+
+func batchStats(id string, sizes ...int) BatchStats {
+	stats := BatchStats{ID: id, Start: time.Unix(1700000000, 0), Sizes: sizes}
+	for i := range sizes {
+		stats.Leaves = append(stats.Leaves, fmt.Sprintf("leaf-%d", i))
+	}
+	return stats
+}
+
+func sizes(n int, size int) []int {
+	s := make([]int, n)
+	for i := range s {
+		s[i] = size
+	}
+	return s
+}
+
+func TestSealerSplit(t *testing.T) {
+	failing := func([]string) (uint64, error) { return 0, errors.New("rpc unavailable") }
+
+	tests := []struct {
+		name      string
+		maxLeaves int
+		maxBytes  int
+		maxGas    uint64
+		estimate  GasEstimator
+		sizes     []int
+		want      []int
+	}{
+		{name: "no limits", sizes: sizes(10, 100), want: []int{10}},
+		{name: "by leaves", maxLeaves: 4, sizes: sizes(10, 100), want: []int{4, 4, 2}},
+		{name: "leaves fit exactly", maxLeaves: 5, sizes: sizes(10, 100), want: []int{5, 5}},
+		{name: "by bytes", maxBytes: 250, sizes: []int{100, 100, 100, 300, 50}, want: []int{2, 1, 1, 1}},
+		{name: "leaf over max-bytes is not split", maxBytes: 100, sizes: []int{500}, want: []int{1}},
+		{name: "by gas", maxGas: 350, estimate: gasPerLeaf, sizes: sizes(8, 100), want: []int{2, 2, 2, 2}},
+		{name: "by gas with odd halves", maxGas: 250, estimate: gasPerLeaf, sizes: sizes(5, 100), want: []int{2, 1, 2}},
+		{name: "gas fits", maxGas: 1000, estimate: gasPerLeaf, sizes: sizes(10, 100), want: []int{10}},
+		{name: "leaf over max-gas is not split", maxGas: 50, estimate: gasPerLeaf, sizes: sizes(1, 100), want: []int{1}},
+		{name: "failed estimate does not split", maxGas: 50, estimate: failing, sizes: sizes(6, 100), want: []int{6}},
+		{name: "by leaves then gas", maxLeaves: 4, maxGas: 250, estimate: gasPerLeaf, sizes: sizes(10, 100), want: []int{2, 2, 2, 2, 2}},
+		{name: "by bytes then leaves", maxLeaves: 3, maxBytes: 1000, sizes: sizes(7, 400), want: []int{2, 2, 2, 1}},
+		{name: "empty batch", maxLeaves: 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Sealer{maxLeaves: tt.maxLeaves, maxBytes: tt.maxBytes, maxGas: tt.maxGas, estimate: tt.estimate}
+			got := s.Split(batchStats("batch", tt.sizes...))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Split = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// settle calls ShouldSeal until no estimate is running, and returns the
+// answer once the estimate it started has finished
+func settle(t *testing.T, p *GasPolicy, batch BatchStats) (bool, string) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		seal, reason := p.ShouldSeal(batch, time.Now())
+		if !p.running {
+			return seal, reason
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("gas estimate did not finish")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestGasPolicyDoesNotBlock(t *testing.T) {
+	release := make(chan struct{})
+	var calls atomic.Int32
+	policy := &GasPolicy{MaxGas: 300, CheckEvery: 2, Estimate: func(leaves []string) (uint64, error) {
+		calls.Add(1)
+		<-release
+		return gasPerLeaf(leaves)
+	}}
+
+	if seal, _ := policy.ShouldSeal(batchStats("b1", 1), time.Now()); seal || calls.Load() != 0 {
+		t.Fatalf("sealed %v or estimated %d times before gas-check-every leaves", seal, calls.Load())
+	}
+
+	// The estimate blocks until released, ShouldSeal must not
+	done := make(chan bool)
+	go func() {
+		seal, _ := policy.ShouldSeal(batchStats("b1", sizes(4, 1)...), time.Now())
+		done <- seal
+	}()
+	select {
+	case seal := <-done:
+		if seal {
+			t.Fatal("sealed before the estimate finished")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("ShouldSeal waited for the gas estimate")
+	}
+
+	// One estimate runs at a time
+	policy.ShouldSeal(batchStats("b1", sizes(6, 1)...), time.Now())
+	close(release)
+
+	seal, reason := settle(t, policy, batchStats("b1", sizes(6, 1)...))
+	if !seal || !strings.Contains(reason, "max-gas") {
+		t.Fatalf("did not seal on the finished estimate: %v %q", seal, reason)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("estimated %d times, want once", calls.Load())
+	}
+}
+
+func TestGasPolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		maxGas   uint64
+		estimate GasEstimator
+		batches  []BatchStats // given to ShouldSeal in turn
+		seal     bool         // the last batch is sealed
+	}{
+		{
+			name:     "under max-gas",
+			maxGas:   1000,
+			estimate: gasPerLeaf,
+			batches:  []BatchStats{batchStats("b1", sizes(4, 1)...)},
+		},
+		{
+			name:     "reaches max-gas",
+			maxGas:   300,
+			estimate: gasPerLeaf,
+			batches:  []BatchStats{batchStats("b1", sizes(4, 1)...)},
+			seal:     true,
+		},
+		{
+			name:     "estimate of an earlier batch does not seal a new one",
+			maxGas:   300,
+			estimate: gasPerLeaf,
+			batches:  []BatchStats{batchStats("b1", sizes(4, 1)...), batchStats("b2", 1)},
+		},
+		{
+			name:     "failed estimate fails open",
+			maxGas:   300,
+			estimate: func([]string) (uint64, error) { return 0, errors.New("rpc unavailable") },
+			batches:  []BatchStats{batchStats("b1", sizes(4, 1)...)},
+		},
+		{
+			name:     "disabled",
+			estimate: func([]string) (uint64, error) { panic("estimated without max-gas") },
+			batches:  []BatchStats{batchStats("b1", sizes(4, 1)...)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := &GasPolicy{MaxGas: tt.maxGas, CheckEvery: 2, Estimate: tt.estimate}
+			var seal bool
+			for _, batch := range tt.batches {
+				seal, _ = settle(t, policy, batch)
+			}
+			if seal != tt.seal {
+				t.Errorf("sealed %v, want %v", seal, tt.seal)
+			}
+		})
+	}
+}
+
+func TestSealerFailedGasEstimateFallsBackToLeaves(t *testing.T) {
+	s := &Sealer{Policy: AnyPolicy{
+		LeafCountPolicy{MaxLeaves: 3},
+		&GasPolicy{MaxGas: 1, CheckEvery: 1, Estimate: func([]string) (uint64, error) { return 0, errors.New("rpc unavailable") }},
+	}}
+
+	if seal, _ := s.ShouldSeal(batchStats("b1", 1, 1), time.Now()); seal {
+		t.Fatal("sealed below max-leaves")
+	}
+	if seal, reason := s.ShouldSeal(batchStats("b1", 1, 1, 1), time.Now()); !seal || !strings.Contains(reason, "max-leaves") {
+		t.Fatalf("did not seal on max-leaves: %v %q", seal, reason)
+	}
+}
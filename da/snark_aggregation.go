@@ -0,0 +1,236 @@
+package da
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"github.com/Layer-Edge/bitcoin-da/config"
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	groth16bn254 "github.com/consensys/gnark/backend/groth16/bn254"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_bn254"
+	"github.com/consensys/gnark/std/math/emulated"
+	stdgroth16 "github.com/consensys/gnark/std/recursion/groth16"
+)
+
+// Aggregation backends selectable through `aggregation-backend`
+const (
+	AggregationBackendMerkle = "merkle"
+	AggregationBackendSnark  = "snark"
+)
+
+// SnarkAggregationCircuit recursively verifies a fixed number of BN254
+// Groth16 proofs of one inner circuit. The public witnesses of the inner
+// proofs, in leaf order, are the public inputs of the aggregated proof.
+type SnarkAggregationCircuit struct {
+	Proofs    []stdgroth16.Proof[sw_bn254.G1Affine, sw_bn254.G2Affine]
+	Witnesses []stdgroth16.Witness[sw_bn254.ScalarField] `gnark:",public"`
+
+	// The inner verifying key is a constant of the circuit
+	InnerKey stdgroth16.VerifyingKey[sw_bn254.G1Affine, sw_bn254.G2Affine, sw_bn254.GTEl] `gnark:"-"`
+}
+
+func (c *SnarkAggregationCircuit) Define(api frontend.API) error {
+	verifier, err := stdgroth16.NewVerifier[sw_bn254.ScalarField, sw_bn254.G1Affine, sw_bn254.G2Affine, sw_bn254.GTEl](api)
+	if err != nil {
+		return fmt.Errorf("new verifier: %w", err)
+	}
+	for i := range c.Proofs {
+		if err := verifier.AssertProof(c.InnerKey, c.Proofs[i], c.Witnesses[i]); err != nil {
+			return fmt.Errorf("proof %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// NewSnarkAggregationCircuit returns the aggregation circuit for batchSize
+// proofs of the circuit of innerKey
+func NewSnarkAggregationCircuit(innerKey groth16.VerifyingKey, batchSize int) (*SnarkAggregationCircuit, error) {
+	key, ok := innerKey.(*groth16bn254.VerifyingKey)
+	if !ok {
+		return nil, fmt.Errorf("inner verifying key is %T, expected a BN254 key", innerKey)
+	}
+	if len(key.CommitmentKeys) > 0 {
+		return nil, fmt.Errorf("inner circuits with commitments are not supported")
+	}
+	if batchSize < 1 {
+		return nil, fmt.Errorf("batch size must be at least 1")
+	}
+
+	fixedKey, err := stdgroth16.ValueOfVerifyingKeyFixed[sw_bn254.G1Affine, sw_bn254.G2Affine, sw_bn254.GTEl](innerKey)
+	if err != nil {
+		return nil, fmt.Errorf("inner verifying key: %w", err)
+	}
+
+	circuit := &SnarkAggregationCircuit{
+		Proofs:    make([]stdgroth16.Proof[sw_bn254.G1Affine, sw_bn254.G2Affine], batchSize),
+		Witnesses: make([]stdgroth16.Witness[sw_bn254.ScalarField], batchSize),
+		InnerKey:  fixedKey,
+	}
+	for i := range circuit.Witnesses {
+		circuit.Witnesses[i].Public = make([]emulated.Element[sw_bn254.ScalarField], innerKey.NbPublicWitness())
+	}
+	return circuit, nil
+}
+
+// CompileSnarkAggregationCircuit compiles the aggregation circuit. Compiling
+// is deterministic, so keys from one setup stay valid across restarts.
+func CompileSnarkAggregationCircuit(innerKey groth16.VerifyingKey, batchSize int) (constraint.ConstraintSystem, error) {
+	circuit, err := NewSnarkAggregationCircuit(innerKey, batchSize)
+	if err != nil {
+		return nil, err
+	}
+	return frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, circuit)
+}
+
+// SnarkAggregate is the output of the snark backend for one batch
+type SnarkAggregate struct {
+	Proof        []byte // binary BN254 Groth16 proof of the aggregation circuit
+	PublicInputs []byte // binary public witness: the inner public witnesses, padded to the batch size
+}
+
+// SnarkAggregator aggregates batches of Groth16 proofs of one inner circuit
+// into a single recursive Groth16 proof
+type SnarkAggregator struct {
+	inner     *GnarkValidator
+	batchSize int
+
+	ccs constraint.ConstraintSystem
+	pk  groth16.ProvingKey
+	vk  groth16.VerifyingKey
+}
+
+// NewSnarkAggregator compiles the aggregation circuit and loads its keys
+func NewSnarkAggregator(cfg *config.Config) (*SnarkAggregator, error) {
+	data, err := os.ReadFile(cfg.SnarkAggregation.InnerVerifyingKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read inner verifying key: %w", err)
+	}
+	inner, err := parseGnarkValidator("groth16", ecc.BN254, data)
+	if err != nil {
+		return nil, fmt.Errorf("inner verifying key: %w", err)
+	}
+
+	a := &SnarkAggregator{inner: inner, batchSize: cfg.SnarkAggregation.BatchSize}
+
+	start := time.Now()
+	a.ccs, err = CompileSnarkAggregationCircuit(inner.groth16Key, a.batchSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile aggregation circuit: %w", err)
+	}
+	log.Printf("Compiled SNARK aggregation circuit for %d proofs: %d constraints in %s",
+		a.batchSize, a.ccs.GetNbConstraints(), time.Since(start))
+
+	a.pk = groth16.NewProvingKey(ecc.BN254)
+	if err := readKeyFile(cfg.SnarkAggregation.ProvingKey, a.pk); err != nil {
+		return nil, fmt.Errorf("proving key: %w", err)
+	}
+	a.vk = groth16.NewVerifyingKey(ecc.BN254)
+	if err := readKeyFile(cfg.SnarkAggregation.VerifyingKey, a.vk); err != nil {
+		return nil, fmt.Errorf("verifying key: %w", err)
+	}
+	// The key counts the commitments of the circuit among its public inputs
+	expected := a.ccs.GetNbPublicVariables() - 1 + len(a.ccs.GetCommitments().(constraint.Groth16Commitments))
+	if a.vk.NbPublicWitness() != expected {
+		return nil, fmt.Errorf("verifying key has %d public inputs, the circuit has %d: keys are from another batch size or inner circuit",
+			a.vk.NbPublicWitness(), expected)
+	}
+	return a, nil
+}
+
+// ReadGroth16VerifyingKey loads a binary BN254 Groth16 verifying key
+func ReadGroth16VerifyingKey(path string) (groth16.VerifyingKey, error) {
+	vk := groth16.NewVerifyingKey(ecc.BN254)
+	if err := readKeyFile(path, vk); err != nil {
+		return nil, err
+	}
+	return vk, nil
+}
+
+// readKeyFile decodes a binary gnark key from path into key
+func readKeyFile(path string, key io.ReaderFrom) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if _, err := key.ReadFrom(bufio.NewReader(file)); err != nil {
+		return fmt.Errorf("failed to decode %s: %w", path, err)
+	}
+	return nil
+}
+
+// BatchSize is the number of proofs the circuit verifies
+func (a *SnarkAggregator) BatchSize() int {
+	return a.batchSize
+}
+
+// Validate checks that proof is a valid proof of the inner circuit, the only
+// proofs this backend can aggregate
+func (a *SnarkAggregator) Validate(proof []byte) error {
+	return a.inner.Validate(proof)
+}
+
+// Aggregate proves that every payload is a valid proof of the inner circuit.
+// Batches smaller than the circuit are padded by repeating the last proof.
+func (a *SnarkAggregator) Aggregate(payloads [][]byte) (*SnarkAggregate, error) {
+	if len(payloads) == 0 || len(payloads) > a.batchSize {
+		return nil, fmt.Errorf("cannot aggregate %d proofs with a batch size of %d", len(payloads), a.batchSize)
+	}
+
+	assignment := &SnarkAggregationCircuit{
+		Proofs:    make([]stdgroth16.Proof[sw_bn254.G1Affine, sw_bn254.G2Affine], a.batchSize),
+		Witnesses: make([]stdgroth16.Witness[sw_bn254.ScalarField], a.batchSize),
+	}
+	for i := range assignment.Proofs {
+		payload := payloads[min(i, len(payloads)-1)]
+		proof, publicWitness, err := decodeGroth16Payload(ecc.BN254, payload)
+		if err != nil {
+			return nil, fmt.Errorf("proof %d: %w", i, err)
+		}
+		if assignment.Proofs[i], err = stdgroth16.ValueOfProof[sw_bn254.G1Affine, sw_bn254.G2Affine](proof); err != nil {
+			return nil, fmt.Errorf("proof %d: %w", i, err)
+		}
+		if assignment.Witnesses[i], err = stdgroth16.ValueOfWitness[sw_bn254.ScalarField](publicWitness); err != nil {
+			return nil, fmt.Errorf("proof %d: %w", i, err)
+		}
+	}
+
+	fullWitness, err := frontend.NewWitness(assignment, ecc.BN254.ScalarField())
+	if err != nil {
+		return nil, fmt.Errorf("failed to build witness: %w", err)
+	}
+	publicWitness, err := fullWitness.Public()
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	proof, err := groth16.Prove(a.ccs, a.pk, fullWitness)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prove aggregation: %w", err)
+	}
+	if err := groth16.Verify(proof, a.vk, publicWitness); err != nil {
+		return nil, fmt.Errorf("aggregated proof does not verify: %w", err)
+	}
+	log.Printf("Aggregated %d proofs into a SNARK in %s", len(payloads), time.Since(start))
+
+	var proofBytes bytes.Buffer
+	if _, err := proof.WriteTo(&proofBytes); err != nil {
+		return nil, err
+	}
+	publicInputs, err := publicWitness.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return &SnarkAggregate{Proof: proofBytes.Bytes(), PublicInputs: publicInputs}, nil
+}
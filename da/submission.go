@@ -0,0 +1,86 @@
+package da
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Layer-Edge/bitcoin-da/utils"
+)
+
+// Submission is a proof handed to HashBlockSubscriber by a transport other
+// than the data socket
+type Submission struct {
+	Namespace string
+	Label     string
+	ProofType string // empty uses proof-validation.default-type
+	Proof     []byte
+	Signature []byte // signature frame, see EncodeSubmissionSignature
+
+	reply chan *Ack
+}
+
+var (
+	// ErrWriterBusy means the writer did not take the submission, which is safe to retry
+	ErrWriterBusy = errors.New("writer did not accept the submission")
+	// ErrSubmissionUnacknowledged means the writer took the submission but
+	// did not answer in time, so the proof may have been stored
+	ErrSubmissionUnacknowledged = errors.New("writer did not acknowledge the submission")
+)
+
+// submissions feeds the HashBlockSubscriber loop alongside the data socket
+var submissions = make(chan *Submission)
+
+// SubmitProof queues a proof with the running HashBlockSubscriber and waits
+// for its ACK. An empty namespace is derived from the label. It fails with
+// ErrWriterBusy or ErrSubmissionUnacknowledged if the writer does not take or
+// answer the submission before ctx is done.
+func SubmitProof(ctx context.Context, sub *Submission) (*Ack, error) {
+	if sub.Label == "" {
+		return NewAck(AckStatusRejected, "", "label is empty"), nil
+	}
+	if sub.Namespace == "" {
+		sub.Namespace = NamespaceFromLabel(sub.Label)
+	}
+	if err := ValidateNamespace(sub.Namespace); err != nil {
+		return NewAck(AckStatusRejected, "", err.Error()), nil
+	}
+	if len(sub.Proof) == 0 {
+		return NewAck(AckStatusRejected, "", "proof is empty"), nil
+	}
+	if sub.Label == StatusQueryLabel {
+		return NewAck(AckStatusRejected, utils.Keccak256Hash(sub.Proof), fmt.Sprintf("label %q is reserved for status queries", StatusQueryLabel)), nil
+	}
+
+	sub.reply = make(chan *Ack, 1)
+
+	// select picks at random when both cases are ready, so a submission
+	// past its deadline must not reach a writer that is ready to take it
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrWriterBusy, err)
+	}
+
+	select {
+	case submissions <- sub:
+	case <-ctx.Done():
+		return nil, fmt.Errorf("%w: %w", ErrWriterBusy, ctx.Err())
+	}
+
+	select {
+	case ack := <-sub.reply:
+		return ack, nil
+	case <-ctx.Done():
+		return nil, fmt.Errorf("%w: %w", ErrSubmissionUnacknowledged, ctx.Err())
+	}
+}
+
+// SubmissionErrorAck is the ACK for a proof SubmitProof failed to submit.
+// A proof the writer took without answering gets an unknown ACK, as it may
+// be stored already.
+func SubmissionErrorAck(proof []byte, err error) *Ack {
+	proofID := utils.Keccak256Hash(proof)
+	if errors.Is(err, ErrSubmissionUnacknowledged) {
+		return NewAck(AckStatusUnknown, proofID, "writer did not answer in time, the proof may be stored: query its status by proof id before retrying")
+	}
+	return NewAck(AckStatusFailed, proofID, "writer is busy, please retry")
+}
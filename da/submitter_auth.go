@@ -0,0 +1,237 @@
+package da
+
+import (
+	"crypto/ed25519"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Layer-Edge/bitcoin-da/config"
+	"github.com/Layer-Edge/bitcoin-da/models"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Signature schemes, the first byte of a submission signature frame
+const (
+	SignatureSchemeSecp256k1 byte = 0x01
+	SignatureSchemeEd25519   byte = 0x02
+)
+
+var signatureSchemeNames = map[byte]string{
+	SignatureSchemeSecp256k1: "secp256k1",
+	SignatureSchemeEd25519:   "ed25519",
+}
+
+// secp256k1 keys are compressed; signatures may carry the recovery byte,
+// which is ignored
+const (
+	secp256k1PubKeySize    = 33
+	secp256k1SignatureSize = 64
+)
+
+// Submitter is an authorised submission key
+type Submitter struct {
+	Name       string
+	Scheme     byte
+	PublicKey  []byte
+	Namespaces []string // empty allows every namespace
+}
+
+// SubmitterRegistry resolves submission keys from the config and/or the
+// submitter_keys table
+type SubmitterRegistry struct {
+	static      map[string]*Submitter
+	usePostgres bool
+}
+
+// NewSubmitterRegistry loads the submitter-auth keys from the config
+func NewSubmitterRegistry(cfg *config.Config) (*SubmitterRegistry, error) {
+	r := &SubmitterRegistry{
+		static:      map[string]*Submitter{},
+		usePostgres: cfg.SubmitterAuth.Registry != "config",
+	}
+	if cfg.SubmitterAuth.Registry == "postgres" {
+		return r, nil
+	}
+
+	for _, s := range cfg.SubmitterAuth.Submitters {
+		submitter, err := newSubmitter(s.Name, s.Scheme, s.PublicKey, s.Namespaces)
+		if err != nil {
+			return nil, fmt.Errorf("submitter %q: %w", s.Name, err)
+		}
+		r.static[submitterKey(submitter.Scheme, submitter.PublicKey)] = submitter
+	}
+	return r, nil
+}
+
+func newSubmitter(name string, scheme string, publicKey string, namespaces []string) (*Submitter, error) {
+	var schemeByte byte
+	for b, n := range signatureSchemeNames {
+		if n == scheme {
+			schemeByte = b
+		}
+	}
+	if schemeByte == 0 {
+		return nil, fmt.Errorf("unknown signature scheme %q", scheme)
+	}
+
+	key, err := hex.DecodeString(strings.TrimPrefix(publicKey, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key: %w", err)
+	}
+	key, err = normalizePublicKey(schemeByte, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Submitter{Name: name, Scheme: schemeByte, PublicKey: key, Namespaces: namespaces}, nil
+}
+
+// normalizePublicKey checks the key size for scheme and compresses
+// uncompressed secp256k1 keys
+func normalizePublicKey(scheme byte, key []byte) ([]byte, error) {
+	switch scheme {
+	case SignatureSchemeSecp256k1:
+		if len(key) == 65 {
+			pub, err := crypto.UnmarshalPubkey(key)
+			if err != nil {
+				return nil, fmt.Errorf("invalid secp256k1 public key: %w", err)
+			}
+			return crypto.CompressPubkey(pub), nil
+		}
+		if _, err := crypto.DecompressPubkey(key); err != nil {
+			return nil, fmt.Errorf("invalid secp256k1 public key: %w", err)
+		}
+		return key, nil
+	case SignatureSchemeEd25519:
+		if len(key) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("ed25519 public key has %d bytes, expected %d", len(key), ed25519.PublicKeySize)
+		}
+		return key, nil
+	default:
+		return nil, fmt.Errorf("unknown signature scheme %d", scheme)
+	}
+}
+
+func submitterKey(scheme byte, publicKey []byte) string {
+	return signatureSchemeNames[scheme] + ":" + hex.EncodeToString(publicKey)
+}
+
+// SubmissionDigest is the message submitters sign: keccak256(namespace 0x00 proof)
+func SubmissionDigest(namespace string, proof []byte) []byte {
+	return crypto.Keccak256([]byte(namespace), []byte{0}, proof)
+}
+
+// EncodeSubmissionSignature builds the signature frame
+//
+//	<scheme> <public key> <signature>
+func EncodeSubmissionSignature(scheme byte, publicKey []byte, signature []byte) []byte {
+	frame := make([]byte, 0, 1+len(publicKey)+len(signature))
+	frame = append(frame, scheme)
+	frame = append(frame, publicKey...)
+	return append(frame, signature...)
+}
+
+// ParseSubmissionSignature splits a signature frame into its parts
+func ParseSubmissionSignature(frame []byte) (scheme byte, publicKey []byte, signature []byte, err error) {
+	if len(frame) == 0 {
+		return 0, nil, nil, fmt.Errorf("submission is not signed")
+	}
+
+	scheme, frame = frame[0], frame[1:]
+	switch scheme {
+	case SignatureSchemeSecp256k1:
+		if len(frame) != secp256k1PubKeySize+secp256k1SignatureSize && len(frame) != secp256k1PubKeySize+crypto.SignatureLength {
+			return 0, nil, nil, fmt.Errorf("secp256k1 signature frame has %d bytes, expected a 33 byte compressed key and a 64 or 65 byte signature", len(frame))
+		}
+		return scheme, frame[:secp256k1PubKeySize], frame[secp256k1PubKeySize:], nil
+	case SignatureSchemeEd25519:
+		if len(frame) != ed25519.PublicKeySize+ed25519.SignatureSize {
+			return 0, nil, nil, fmt.Errorf("ed25519 signature frame has %d bytes, expected a 32 byte key and a 64 byte signature", len(frame))
+		}
+		return scheme, frame[:ed25519.PublicKeySize], frame[ed25519.PublicKeySize:], nil
+	default:
+		return 0, nil, nil, fmt.Errorf("unknown signature scheme %d", scheme)
+	}
+}
+
+// SignSubmission signs namespace and proof and returns the signature frame.
+// privateKey is a 32 byte secp256k1 key or a 32 byte ed25519 seed.
+func SignSubmission(scheme byte, privateKey []byte, namespace string, proof []byte) ([]byte, error) {
+	digest := SubmissionDigest(namespace, proof)
+
+	switch scheme {
+	case SignatureSchemeSecp256k1:
+		key, err := crypto.ToECDSA(privateKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid secp256k1 private key: %w", err)
+		}
+		sig, err := crypto.Sign(digest, key)
+		if err != nil {
+			return nil, err
+		}
+		return EncodeSubmissionSignature(scheme, crypto.CompressPubkey(&key.PublicKey), sig[:secp256k1SignatureSize]), nil
+	case SignatureSchemeEd25519:
+		if len(privateKey) != ed25519.SeedSize {
+			return nil, fmt.Errorf("ed25519 seed has %d bytes, expected %d", len(privateKey), ed25519.SeedSize)
+		}
+		key := ed25519.NewKeyFromSeed(privateKey)
+		return EncodeSubmissionSignature(scheme, key.Public().(ed25519.PublicKey), ed25519.Sign(key, digest)), nil
+	default:
+		return nil, fmt.Errorf("unknown signature scheme %d", scheme)
+	}
+}
+
+// Lookup returns the authorised submitter for the key, or an error if the
+// key is not in the registry
+func (r *SubmitterRegistry) Lookup(scheme byte, publicKey []byte) (*Submitter, error) {
+	if s, ok := r.static[submitterKey(scheme, publicKey)]; ok {
+		return s, nil
+	}
+
+	if r.usePostgres {
+		key, err := models.GetSubmitterKey(signatureSchemeNames[scheme], hex.EncodeToString(publicKey))
+		if err == nil {
+			return newSubmitter(key.Name, key.Scheme, key.PublicKey, key.Namespaces)
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("failed to look up submitter key: %w", err)
+		}
+	}
+
+	return nil, fmt.Errorf("unknown submitter key %s", submitterKey(scheme, publicKey))
+}
+
+// Verify checks that frame is a valid signature over namespace and proof by
+// a submitter that may submit to namespace
+func (r *SubmitterRegistry) Verify(namespace string, proof []byte, frame []byte) (*Submitter, error) {
+	scheme, publicKey, signature, err := ParseSubmissionSignature(frame)
+	if err != nil {
+		return nil, err
+	}
+
+	submitter, err := r.Lookup(scheme, publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	digest := SubmissionDigest(namespace, proof)
+	valid := false
+	switch scheme {
+	case SignatureSchemeSecp256k1:
+		valid = crypto.VerifySignature(publicKey, digest, signature[:secp256k1SignatureSize])
+	case SignatureSchemeEd25519:
+		valid = ed25519.Verify(publicKey, digest, signature)
+	}
+	if !valid {
+		return nil, fmt.Errorf("invalid %s signature from submitter %q", signatureSchemeNames[scheme], submitter.Name)
+	}
+
+	if len(submitter.Namespaces) > 0 && !slices.Contains(submitter.Namespaces, namespace) {
+		return nil, fmt.Errorf("submitter %q may not submit to namespace %q", submitter.Name, namespace)
+	}
+	return submitter, nil
+}
@@ -2,6 +2,7 @@ package da
 
 import (
 	"encoding/hex"
+	"fmt"
 	"log"
 	"strings"
 	"time"
@@ -11,9 +12,39 @@ import (
 	"github.com/Layer-Edge/bitcoin-da/models"
 )
 
+// ProcessBTCMsg anchors the root in msg as a versioned payload. Payloads over
+// the OP_RETURN limit are split across linked anchors if op-return.split-oversized
+// is set and refused otherwise.
 func ProcessBTCMsg(msg []byte, protocolId string) ([]byte, error) {
-	data := append([]byte(protocolId), msg...)
-	hash := CreateOPReturnTransaction(hex.EncodeToString(data))
+	payload, err := RootAnchorPayload(protocolId, string(msg))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(payload) <= opReturnDataLimit {
+		hash := CreateOPReturnTransaction(hex.EncodeToString(payload))
+		return []byte(hash), nil
+	}
+
+	if !splitOversizedPayloads {
+		return nil, fmt.Errorf("anchor payload of %d bytes exceeds the %d byte OP_RETURN limit; shorten protocol-id or enable op-return.split-oversized",
+			len(payload), opReturnDataLimit)
+	}
+
+	chunks, err := SplitAnchorPayload(protocolId, payload, opReturnDataLimit)
+	if err != nil {
+		return nil, err
+	}
+	parts := make([]string, len(chunks))
+	for i, chunk := range chunks {
+		parts[i] = hex.EncodeToString(chunk)
+	}
+
+	log.Printf("Anchor payload of %d bytes split into %d linked anchors", len(payload), len(parts))
+	hash, err := CreateLinkedOPReturnTransactions(parts)
+	if err != nil {
+		return nil, err
+	}
 	return []byte(hash), nil
 }
 
@@ -30,6 +61,10 @@ func SuperProofCronJob(cfg *config.Config, immediate bool) {
 	}()
 
 	InitOPReturnRPC(cfg.BtcEndpoint, cfg.Auth, cfg.WalletPassphrase)
+	InitFeeEstimation(cfg)
+	InitCoinSelection(cfg)
+	InitInscription(cfg)
+	InitOPReturnPolicy(cfg)
 
 	log.Println("Starting Super Proof Cron Job")
 	log.Println("Super proof will run 4 times daily at 12:00 AM, 6:00 AM, 12:00 PM, and 6:00 PM UTC")
@@ -75,6 +110,10 @@ func NonBTCTxSuperProofCronJob(cfg *config.Config, immediate bool) {
 	}()
 
 	InitOPReturnRPC(cfg.BtcEndpoint, cfg.Auth, cfg.WalletPassphrase)
+	InitFeeEstimation(cfg)
+	InitCoinSelection(cfg)
+	InitInscription(cfg)
+	InitOPReturnPolicy(cfg)
 
 	log.Println("Starting Non BTC TX Super Proof Cron Job")
 	log.Println("Super proof will run at 1:00 AM, 7:00 AM, 1:00 PM, and 7:00 PM UTC")
@@ -163,8 +202,8 @@ func processSuperProof(cfg *config.Config) {
 	}()
 
 	// Generate super proof (merkle tree of all merkle roots)
-	prf := ZKProof{}
-	superMerkleRoot := prf.GenerateAggregatedProof(strings.Join(merkleRoots, ""))
+	prf := NewZKProof(cfg)
+	superMerkleRoot := prf.GenerateAggregatedProof(merkleRoots)
 
 	if superMerkleRoot == "" {
 		log.Println("Failed to generate super proof, skipping write")
@@ -175,6 +214,9 @@ func processSuperProof(cfg *config.Config) {
 
 	// Process BTC transaction for the super proof
 	fnBtc := func(msg [][]byte) ([]byte, error) {
+		if cfg.AnchorMode.SuperProof == AnchorModeInscription {
+			return ProcessBTCInscription(superMerkleRoot, merkleRoots, cfg.ProtocolId)
+		}
 		hash, err := ProcessBTCMsg(msg[1], cfg.ProtocolId)
 		return hash, err
 	}
@@ -249,16 +291,19 @@ func processNonBTCTxSuperProof(cfg *config.Config) {
 		}
 	}()
 
+	superProof := superProofWithoutBTCTxHash[0]
+
 	fnBtc := func(msg [][]byte) ([]byte, error) {
+		if cfg.AnchorMode.SuperProofRetry == AnchorModeInscription {
+			return ProcessBTCInscription(superProof.MerkleRoot, superProof.Proofs, cfg.ProtocolId)
+		}
 		hash, err := ProcessBTCMsg(msg[1], cfg.ProtocolId)
 		return hash, err
 	}
 
-	superProof := superProofWithoutBTCTxHash[0]
-
 	log.Printf("Processing super proof without BTC TX hash: %s", superProof.ID)
 
-	hash, err := dataReader.ProcessOutTuple(fnBtc, [][]byte{nil, superProof.AggregateProof})
+	hash, err := dataReader.ProcessOutTuple(fnBtc, [][]byte{nil, []byte(superProof.MerkleRoot)})
 	if err != nil {
 		log.Printf("Error writing super proof to BTC: %v", err)
 		return
@@ -37,6 +37,13 @@ func main() {
 	hashBlockDone := make(chan error, 1)
 	superProofDone := make(chan error, 1)
 	failedSuperProofDone := make(chan error, 1)
+	confirmationTrackerDone := make(chan error, 1)
+	// Optional services keep a nil channel when disabled so they never fire below
+	var btcReaderDone chan error
+	var feeBumperDone chan error
+	var consolidationDone chan error
+	var apiDone chan error
+	var grpcDone chan error
 
 	log.Println("Starting Bitcoin DA services...")
 	utils.LogSystemError("main", "Services starting", nil, map[string]interface{}{
@@ -85,6 +92,105 @@ func main() {
 		failedSuperProofDone <- nil
 	}()
 
+	// Start BTCConfirmationTracker service
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				utils.RecoverFromPanic("BTCConfirmationTracker")
+				confirmationTrackerDone <- fmt.Errorf("BTCConfirmationTracker panic: %v", r)
+			}
+		}()
+
+		log.Println("Starting BTCConfirmationTracker...")
+		da.BTCConfirmationTracker(&cfg)
+		confirmationTrackerDone <- nil
+	}()
+
+	// Start FeeBumperJob service
+	if cfg.FeeBumper.Enabled {
+		feeBumperDone = make(chan error, 1)
+		go func() {
+			defer func() {
+				if r := recover(); r != nil {
+					utils.RecoverFromPanic("FeeBumperJob")
+					feeBumperDone <- fmt.Errorf("FeeBumperJob panic: %v", r)
+				}
+			}()
+
+			log.Println("Starting FeeBumperJob...")
+			da.FeeBumperJob(&cfg)
+			feeBumperDone <- nil
+		}()
+	}
+
+	// Start UTXOConsolidationJob service
+	if cfg.CoinSelection.Consolidation.Enabled {
+		consolidationDone = make(chan error, 1)
+		go func() {
+			defer func() {
+				if r := recover(); r != nil {
+					utils.RecoverFromPanic("UTXOConsolidationJob")
+					consolidationDone <- fmt.Errorf("UTXOConsolidationJob panic: %v", r)
+				}
+			}()
+
+			log.Println("Starting UTXOConsolidationJob...")
+			da.UTXOConsolidationJob(&cfg)
+			consolidationDone <- nil
+		}()
+	}
+
+	// Start APIServer service
+	if cfg.API.Enabled {
+		apiDone = make(chan error, 1)
+		go func() {
+			defer func() {
+				if r := recover(); r != nil {
+					utils.RecoverFromPanic("APIServer")
+					apiDone <- fmt.Errorf("APIServer panic: %v", r)
+				}
+			}()
+
+			log.Println("Starting APIServer...")
+			da.APIServer(&cfg)
+			apiDone <- nil
+		}()
+	}
+
+	// Start GRPCServer service
+	if cfg.GRPC.Enabled {
+		grpcDone = make(chan error, 1)
+		go func() {
+			defer func() {
+				if r := recover(); r != nil {
+					utils.RecoverFromPanic("GRPCServer")
+					grpcDone <- fmt.Errorf("GRPCServer panic: %v", r)
+				}
+			}()
+
+			log.Println("Starting GRPCServer...")
+			da.GRPCServer(&cfg)
+			grpcDone <- nil
+		}()
+	}
+
+	// Start BTCReader service
+	if cfg.Reader.Enabled {
+		btcReaderDone = make(chan error, 1)
+		go func() {
+			defer func() {
+				if r := recover(); r != nil {
+					utils.RecoverFromPanic("BTCReader")
+					btcReaderDone <- fmt.Errorf("BTCReader panic: %v", r)
+				}
+			}()
+
+			log.Println("Starting BTCReader...")
+			da.BTCReader(&cfg)
+			btcReaderDone <- nil
+		}()
+	}
+
 	// Wait for either shutdown signal or service completion
 	select {
 	case sig := <-sigChan:
@@ -105,6 +211,22 @@ func main() {
 			<-hashBlockDone
 			<-superProofDone
 			<-failedSuperProofDone
+			<-confirmationTrackerDone
+			if btcReaderDone != nil {
+				<-btcReaderDone
+			}
+			if feeBumperDone != nil {
+				<-feeBumperDone
+			}
+			if consolidationDone != nil {
+				<-consolidationDone
+			}
+			if apiDone != nil {
+				<-apiDone
+			}
+			if grpcDone != nil {
+				<-grpcDone
+			}
 			servicesShutdown <- true
 		}()
 
@@ -137,5 +259,47 @@ func main() {
 			log.Fatalf("NonBTCTxSuperProofCronJob failed: %v", err)
 		}
 		log.Println("NonBTCTxSuperProofCronJob completed normally")
+
+	case err := <-confirmationTrackerDone:
+		if err != nil {
+			utils.LogCriticalError("main", "BTCConfirmationTracker failed", err, nil)
+			log.Fatalf("BTCConfirmationTracker failed: %v", err)
+		}
+		log.Println("BTCConfirmationTracker completed normally")
+
+	case err := <-btcReaderDone:
+		if err != nil {
+			utils.LogCriticalError("main", "BTCReader failed", err, nil)
+			log.Fatalf("BTCReader failed: %v", err)
+		}
+		log.Println("BTCReader completed normally")
+
+	case err := <-feeBumperDone:
+		if err != nil {
+			utils.LogCriticalError("main", "FeeBumperJob failed", err, nil)
+			log.Fatalf("FeeBumperJob failed: %v", err)
+		}
+		log.Println("FeeBumperJob completed normally")
+
+	case err := <-consolidationDone:
+		if err != nil {
+			utils.LogCriticalError("main", "UTXOConsolidationJob failed", err, nil)
+			log.Fatalf("UTXOConsolidationJob failed: %v", err)
+		}
+		log.Println("UTXOConsolidationJob completed normally")
+
+	case err := <-apiDone:
+		if err != nil {
+			utils.LogCriticalError("main", "APIServer failed", err, nil)
+			log.Fatalf("APIServer failed: %v", err)
+		}
+		log.Println("APIServer completed normally")
+
+	case err := <-grpcDone:
+		if err != nil {
+			utils.LogCriticalError("main", "GRPCServer failed", err, nil)
+			log.Fatalf("GRPCServer failed: %v", err)
+		}
+		log.Println("GRPCServer completed normally")
 	}
 }
@@ -0,0 +1,162 @@
+package merkle
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Tree is a binary keccak256 Merkle tree built with sorted-pair hashing.
+// Leaves are the 32-byte values handed to MerkleTreeStorage.storeTree, so a
+// submitted proof's leaf is keccak256(proof bytes). When a level has an odd
+// number of nodes, the last node is promoted to the next level unchanged.
+type Tree struct {
+	levels [][]common.Hash
+}
+
+// HashLeaf returns the keccak256 leaf value for raw proof bytes
+func HashLeaf(data []byte) common.Hash {
+	return crypto.Keccak256Hash(data)
+}
+
+// HashPair hashes two nodes in ascending byte order so that verifiers do not
+// need to know whether a sibling sits on the left or the right
+func HashPair(a, b common.Hash) common.Hash {
+	if bytes.Compare(a[:], b[:]) > 0 {
+		a, b = b, a
+	}
+	return crypto.Keccak256Hash(a[:], b[:])
+}
+
+// ParseLeaf parses a 32-byte hex leaf, with or without the 0x prefix
+func ParseLeaf(leaf string) (common.Hash, error) {
+	leaf = strings.TrimSpace(leaf)
+	if !strings.HasPrefix(leaf, "0x") && !strings.HasPrefix(leaf, "0X") {
+		leaf = "0x" + leaf
+	}
+
+	raw, err := hexutil.Decode(leaf)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("invalid leaf %q: %w", leaf, err)
+	}
+	if len(raw) != common.HashLength {
+		return common.Hash{}, fmt.Errorf("invalid leaf %q: expected %d bytes, got %d", leaf, common.HashLength, len(raw))
+	}
+
+	return common.BytesToHash(raw), nil
+}
+
+// ParseLeaves parses a list of hex leaves, skipping blank entries
+func ParseLeaves(leaves []string) ([]common.Hash, error) {
+	hashes := make([]common.Hash, 0, len(leaves))
+	for _, leaf := range leaves {
+		if strings.TrimSpace(leaf) == "" {
+			continue
+		}
+		h, err := ParseLeaf(leaf)
+		if err != nil {
+			return nil, err
+		}
+		hashes = append(hashes, h)
+	}
+	return hashes, nil
+}
+
+// New builds a tree over the given leaves in insertion order
+func New(leaves []common.Hash) (*Tree, error) {
+	if len(leaves) == 0 {
+		return nil, fmt.Errorf("cannot build merkle tree without leaves")
+	}
+
+	level := make([]common.Hash, len(leaves))
+	copy(level, leaves)
+	levels := [][]common.Hash{level}
+
+	for len(level) > 1 {
+		next := make([]common.Hash, 0, (len(level)+1)/2)
+		for i := 0; i < len(level); i += 2 {
+			if i+1 == len(level) {
+				next = append(next, level[i])
+				continue
+			}
+			next = append(next, HashPair(level[i], level[i+1]))
+		}
+		levels = append(levels, next)
+		level = next
+	}
+
+	return &Tree{levels: levels}, nil
+}
+
+// FromHex parses hex leaves and builds a tree over them
+func FromHex(leaves []string) (*Tree, error) {
+	hashes, err := ParseLeaves(leaves)
+	if err != nil {
+		return nil, err
+	}
+	return New(hashes)
+}
+
+// Root returns the tree root
+func (t *Tree) Root() common.Hash {
+	top := t.levels[len(t.levels)-1]
+	return top[0]
+}
+
+// Leaves returns a copy of the leaf level
+func (t *Tree) Leaves() []common.Hash {
+	leaves := make([]common.Hash, len(t.levels[0]))
+	copy(leaves, t.levels[0])
+	return leaves
+}
+
+// Len returns the number of leaves in the tree
+func (t *Tree) Len() int {
+	return len(t.levels[0])
+}
+
+// IndexOf returns the position of the first occurrence of leaf, or -1
+func (t *Tree) IndexOf(leaf common.Hash) int {
+	for i, l := range t.levels[0] {
+		if l == leaf {
+			return i
+		}
+	}
+	return -1
+}
+
+// Proof returns the sibling path from the leaf at index up to the root.
+// Levels where the node was promoted without a sibling contribute nothing.
+func (t *Tree) Proof(index int) ([]common.Hash, error) {
+	if index < 0 || index >= t.Len() {
+		return nil, fmt.Errorf("leaf index %d out of range [0, %d)", index, t.Len())
+	}
+
+	proof := []common.Hash{}
+	for _, level := range t.levels[:len(t.levels)-1] {
+		sibling := index ^ 1
+		if sibling < len(level) {
+			proof = append(proof, level[sibling])
+		}
+		index /= 2
+	}
+	return proof, nil
+}
+
+// ComputeRoot folds a sibling path over a leaf using sorted-pair hashing
+func ComputeRoot(leaf common.Hash, proof []common.Hash) common.Hash {
+	node := leaf
+	for _, sibling := range proof {
+		node = HashPair(node, sibling)
+	}
+	return node
+}
+
+// Verify checks that leaf is included under root through proof
+func Verify(root common.Hash, leaf common.Hash, proof []common.Hash) bool {
+	return ComputeRoot(leaf, proof) == root
+}
@@ -0,0 +1,161 @@
+package merkle
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// letters returns the leaves keccak256("a"), keccak256("b"), ...
+func letters(n int) []common.Hash {
+	leaves := make([]common.Hash, n)
+	for i := range leaves {
+		leaves[i] = HashLeaf([]byte{byte('a' + i)})
+	}
+	return leaves
+}
+
+// pair is an independent sorted-pair keccak256, the hashing of
+// merkletreejs with sortPairs and of OpenZeppelin's MerkleProof
+func pair(a, b common.Hash) common.Hash {
+	if bytes.Compare(a[:], b[:]) > 0 {
+		a, b = b, a
+	}
+	return crypto.Keccak256Hash(append(append([]byte{}, a[:]...), b[:]...))
+}
+
+func TestRoot(t *testing.T) {
+	l := letters(5)
+
+	tests := []struct {
+		name   string
+		leaves []common.Hash
+		want   common.Hash
+	}{
+		{name: "one leaf is the root", leaves: l[:1], want: l[0]},
+		{name: "two leaves", leaves: l[:2], want: pair(l[0], l[1])},
+		{name: "odd leaf is promoted", leaves: l[:3], want: pair(pair(l[0], l[1]), l[2])},
+		{name: "four leaves", leaves: l[:4], want: pair(pair(l[0], l[1]), pair(l[2], l[3]))},
+		{name: "odd leaf is promoted twice", leaves: l[:5], want: pair(pair(pair(l[0], l[1]), pair(l[2], l[3])), l[4])},
+		{name: "order of a pair does not matter", leaves: []common.Hash{l[1], l[0]}, want: pair(l[0], l[1])},
+		{name: "duplicate leaves", leaves: []common.Hash{l[0], l[0]}, want: pair(l[0], l[0])},
+		{name: "duplicate leaves apart", leaves: []common.Hash{l[0], l[1], l[0]}, want: pair(pair(l[0], l[1]), l[0])},
+		// Root of merkletreejs over keccak256 of ["a", "b", "c"] with sortPairs
+		{name: "merkletreejs vector", leaves: l[:3], want: common.HexToHash("0x5842148bc6ebeb52af882a317c765fccd3ae80589b21a9b8cbf21abb630e46a7")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tree, err := New(tt.leaves)
+			if err != nil {
+				t.Fatalf("New: %v", err)
+			}
+			if tree.Root() != tt.want {
+				t.Errorf("root = %s, want %s", tree.Root().Hex(), tt.want.Hex())
+			}
+			if tree.Len() != len(tt.leaves) {
+				t.Errorf("len = %d, want %d", tree.Len(), len(tt.leaves))
+			}
+		})
+	}
+}
+
+func TestNewWithoutLeaves(t *testing.T) {
+	if _, err := New(nil); err == nil {
+		t.Fatal("built a tree without leaves")
+	}
+}
+
+func TestProofRoundTrip(t *testing.T) {
+	for n := 1; n <= 17; n++ {
+		leaves := letters(n)
+		tree, err := New(leaves)
+		if err != nil {
+			t.Fatalf("New(%d): %v", n, err)
+		}
+
+		for index, leaf := range leaves {
+			proof, err := tree.Proof(index)
+			if err != nil {
+				t.Fatalf("%d leaves: Proof(%d): %v", n, index, err)
+			}
+			if !Verify(tree.Root(), leaf, proof) {
+				t.Errorf("%d leaves: proof of index %d does not verify", n, index)
+			}
+
+			// Any tampered sibling breaks the path
+			for i := range proof {
+				tampered := append([]common.Hash{}, proof...)
+				tampered[i][0] ^= 0xff
+				if Verify(tree.Root(), leaf, tampered) {
+					t.Errorf("%d leaves: proof of index %d verifies with sibling %d tampered", n, index, i)
+				}
+			}
+			if n > 1 && Verify(tree.Root(), HashLeaf([]byte("other")), proof) {
+				t.Errorf("%d leaves: proof of index %d verifies another leaf", n, index)
+			}
+		}
+
+		for _, index := range []int{-1, n} {
+			if _, err := tree.Proof(index); err == nil {
+				t.Errorf("%d leaves: Proof(%d) did not fail", n, index)
+			}
+		}
+	}
+}
+
+func TestProofDuplicateLeaves(t *testing.T) {
+	l := letters(2)
+	tree, err := New([]common.Hash{l[0], l[1], l[0]})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	if index := tree.IndexOf(l[0]); index != 0 {
+		t.Errorf("IndexOf = %d, want the first occurrence", index)
+	}
+	for _, index := range []int{0, 2} {
+		proof, err := tree.Proof(index)
+		if err != nil {
+			t.Fatalf("Proof(%d): %v", index, err)
+		}
+		if !Verify(tree.Root(), l[0], proof) {
+			t.Errorf("proof of duplicate at %d does not verify", index)
+		}
+	}
+	if tree.IndexOf(HashLeaf([]byte("missing"))) != -1 {
+		t.Error("IndexOf found a missing leaf")
+	}
+}
+
+func TestParseLeaf(t *testing.T) {
+	leaf := letters(1)[0]
+
+	tests := []struct {
+		input string
+		ok    bool
+	}{
+		{input: leaf.Hex(), ok: true},
+		{input: leaf.Hex()[2:], ok: true},
+		{input: " 0X" + leaf.Hex()[2:] + " ", ok: true},
+		{input: leaf.Hex()[:64], ok: false},
+		{input: "0xzz", ok: false},
+		{input: "", ok: false},
+	}
+	for _, tt := range tests {
+		got, err := ParseLeaf(tt.input)
+		if (err == nil) != tt.ok {
+			t.Errorf("ParseLeaf(%q) err = %v, want ok %v", tt.input, err, tt.ok)
+			continue
+		}
+		if tt.ok && got != leaf {
+			t.Errorf("ParseLeaf(%q) = %s, want %s", tt.input, got.Hex(), leaf.Hex())
+		}
+	}
+
+	hashes, err := ParseLeaves([]string{leaf.Hex(), " ", ""})
+	if err != nil || len(hashes) != 1 {
+		t.Errorf("ParseLeaves skipped blanks into %v, %v", hashes, err)
+	}
+}
@@ -15,30 +15,67 @@ import (
 type AggregatedProof struct {
 	bun.BaseModel `bun:"table:aggregated_proofs,alias:ap"`
 
-	ID              string    `bun:"id,pk,type:char(24),default:generate_mongo_objectid('mongo_objectid_aggregate_proofs_seq')"`
-	BlockHeight     int64     `bun:"block_height,unique,notnull"`
-	BTCBlockNumber  *int64    `bun:"btc_block_number,type:bigint"`
-	BTCTxHash       *string   `bun:"btc_tx_hash,type:varchar(255)"`
-	From            string    `bun:"from,type:varchar(255),notnull"`
-	GasUsed         int64     `bun:"gas_used,notnull,default:0"`
-	AggregateProof  []byte    `bun:"aggregate_proof,type:bytea,notnull"`
-	Proofs          []string  `bun:"proofs,array,type:text[],notnull,default:'{}'"`
-	To              string    `bun:"to,type:varchar(255),notnull"`
-	TransactionHash string    `bun:"transaction_hash,type:varchar(255),notnull"`
-	TransactionFee  string    `bun:"transaction_fee,type:double precision,default:0"`
-	EdgenPrice      string    `bun:"edgen_price,type:double precision,default:0"`
-	Amount          string    `bun:"amount,type:double precision,notnull"`
-	Success         bool      `bun:"success,notnull,default:false"`
-	Timestamp       time.Time `bun:"timestamp,notnull"`
-	CreatedAt       time.Time `bun:"created_at,auto_create"`
-	UpdatedAt       time.Time `bun:"updated_at,auto_update"`
-}
-
-func CreateAggregatedProof(agg_proof string, proof_list []string, data clients.TxData) (sql.Result, error) {
-	return CreateAggregatedProofWithBTC(agg_proof, proof_list, nil, nil, data)
+	ID               string     `bun:"id,pk,type:char(24),default:generate_mongo_objectid('mongo_objectid_aggregate_proofs_seq')"`
+	BlockHeight      int64      `bun:"block_height,unique,notnull"`
+	BTCBlockNumber   *int64     `bun:"btc_block_number,type:bigint"`
+	BTCTxHash        *string    `bun:"btc_tx_hash,type:varchar(255)"`
+	BTCBlockHash     *string    `bun:"btc_block_hash,type:varchar(64)"`
+	BTCBlockTime     *time.Time `bun:"btc_block_time"`
+	BTCConfirmations int64      `bun:"btc_confirmations,notnull,default:0"`
+	BTCConfirmedAt   *time.Time `bun:"btc_confirmed_at"`
+	From             string     `bun:"from,type:varchar(255),notnull"`
+	GasUsed          int64      `bun:"gas_used,notnull,default:0"`
+	AggregateProof   []byte     `bun:"aggregate_proof,type:bytea,notnull"` // the merkle root, or the SNARK proof of snark aggregates
+	MerkleRoot       string     `bun:"merkle_root,type:varchar(255)"`
+	Backend          string     `bun:"backend,type:varchar(16),notnull,default:'merkle'"` // merkle | snark
+	PublicInputs     []byte     `bun:"public_inputs,type:bytea"`                          // public witness of the SNARK proof
+	Proofs           []string   `bun:"proofs,array,type:text[],notnull,default:'{}'"`
+	To               string     `bun:"to,type:varchar(255),notnull"`
+	TransactionHash  string     `bun:"transaction_hash,type:varchar(255),notnull"`
+	TransactionFee   string     `bun:"transaction_fee,type:double precision,default:0"`
+	EdgenPrice       string     `bun:"edgen_price,type:double precision,default:0"`
+	Amount           string     `bun:"amount,type:double precision,notnull"`
+	Success          bool       `bun:"success,notnull,default:false"`
+	Timestamp        time.Time  `bun:"timestamp,notnull"`
+	CreatedAt        time.Time  `bun:"created_at,auto_create"`
+	UpdatedAt        time.Time  `bun:"updated_at,auto_update"`
+}
+
+// CreateAggregatedProof stores a window aggregated under agg_proof and, in the
+// same transaction, marks pending_ids consumed by it. pending_ids must be in
+// leaf order.
+func CreateAggregatedProof(agg_proof string, proof_list []string, pending_ids []int64, data clients.TxData) (sql.Result, error) {
+	ap, err := newAggregatedProof(agg_proof, proof_list, nil, nil, data)
+	if err != nil {
+		return nil, err
+	}
+	return insertAggregatedProof(ap, pending_ids)
+}
+
+// CreateSnarkAggregatedProof stores a window aggregated into a recursive
+// SNARK, marking pending_ids consumed like CreateAggregatedProof. merkle_root
+// is the root stored on LayerEdge for the same leaves.
+func CreateSnarkAggregatedProof(merkle_root string, snark_proof []byte, public_inputs []byte, proof_list []string, pending_ids []int64, data clients.TxData) (sql.Result, error) {
+	ap, err := newAggregatedProof(merkle_root, proof_list, nil, nil, data)
+	if err != nil {
+		return nil, err
+	}
+	ap.AggregateProof = snark_proof
+	ap.Backend = "snark"
+	ap.PublicInputs = public_inputs
+	return insertAggregatedProof(ap, pending_ids)
 }
 
 func CreateAggregatedProofWithBTC(agg_proof string, proof_list []string, btc_tx_hash *string, btc_block_number *int64, data clients.TxData) (sql.Result, error) {
+	ap, err := newAggregatedProof(agg_proof, proof_list, btc_tx_hash, btc_block_number, data)
+	if err != nil {
+		return nil, err
+	}
+	return insertAggregatedProof(ap, nil)
+}
+
+// newAggregatedProof builds a merkle aggregate record for root
+func newAggregatedProof(root string, proof_list []string, btc_tx_hash *string, btc_block_number *int64, data clients.TxData) (*AggregatedProof, error) {
 	block_height, err := strconv.ParseInt(data.BlockHeight, 10, 64)
 	if err != nil {
 		return nil, fmt.Errorf("error converting block height: %w", err)
@@ -60,7 +97,9 @@ func CreateAggregatedProofWithBTC(agg_proof string, proof_list []string, btc_tx_
 		BlockHeight:     block_height,
 		From:            data.From,
 		GasUsed:         gas_used,
-		AggregateProof:  []byte(agg_proof),
+		AggregateProof:  []byte(root),
+		MerkleRoot:      root,
+		Backend:         "merkle",
 		Proofs:          proof_list,
 		To:              data.To,
 		TransactionHash: data.TransactionHash,
@@ -70,12 +109,17 @@ func CreateAggregatedProofWithBTC(agg_proof string, proof_list []string, btc_tx_
 		Success:         data.Success,
 		Timestamp:       time.Now().UTC(),
 	}
+	return ap, nil
+}
 
+// insertAggregatedProof inserts ap and marks the pending proofs it consumed
+// in one transaction, so a restart never restores proofs of a stored aggregate
+func insertAggregatedProof(ap *AggregatedProof, consumed []int64) (sql.Result, error) {
 	log.Printf("Storing proof info to Postgres DB: %v", *ap)
 
 	// Use retry mechanism for database operation
 	var newAggProof sql.Result
-	err = RetryDBOperation(func() error {
+	err := RetryDBOperation(func() error {
 		db, err := GetDB()
 		if err != nil {
 			return fmt.Errorf("failed to get database connection: %w", err)
@@ -84,13 +128,18 @@ func CreateAggregatedProofWithBTC(agg_proof string, proof_list []string, btc_tx_
 		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
 
-		result, err := db.NewInsert().Model(ap).Exec(ctx)
-		if err != nil {
-			return fmt.Errorf("insert operation failed: %w", err)
-		}
+		return db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+			result, err := tx.NewInsert().Model(ap).Exec(ctx)
+			if err != nil {
+				return fmt.Errorf("insert operation failed: %w", err)
+			}
+			if err := markPendingProofsConsumed(ctx, tx, consumed, ap.MerkleRoot); err != nil {
+				return err
+			}
 
-		newAggProof = result
-		return nil
+			newAggProof = result
+			return nil
+		})
 	})
 
 	if err != nil {
@@ -114,10 +163,10 @@ func GetUnprocessedMerkleRoots(lastProcessedTimestamp time.Time) ([]string, erro
 		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
 
-		// Query for merkle roots (aggregate_proof field) from records created after lastProcessedTimestamp
+		// Query for merkle roots from records created after lastProcessedTimestamp
 		err = db.NewSelect().
 			Model(&AggregatedProof{}).
-			Column("aggregate_proof").
+			Column("merkle_root").
 			Where("timestamp > ?", lastProcessedTimestamp).
 			Order("timestamp ASC").
 			Scan(ctx, &merkleRoots)
@@ -249,3 +298,284 @@ func UpdateSuperProofWithBTCTxHash(id string, btc_tx_hash *string, btc_block_num
 	log.Printf("Updated super proof with BTC TX hash: %s", id)
 	return nil
 }
+
+// IsSuperProof reports whether the record is a super proof. Super proofs are
+// distinguished by having BTCTxHash set (not null)
+func (ap *AggregatedProof) IsSuperProof() bool {
+	return ap.BTCTxHash != nil
+}
+
+// GetAggregatedProofByRoot fetches the aggregated proof (or super proof) whose merkle root is root
+func GetAggregatedProofByRoot(root string) (*AggregatedProof, error) {
+	proof := new(AggregatedProof)
+
+	err := RetryDBOperation(func() error {
+		db, err := GetDB()
+		if err != nil {
+			return fmt.Errorf("failed to get database connection: %w", err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		err = db.NewSelect().
+			Model(proof).
+			Where("merkle_root = ?", root).
+			Order("timestamp DESC").
+			Limit(1).
+			Scan(ctx)
+
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return nil
+			}
+			return fmt.Errorf("failed to fetch aggregated proof by root: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get aggregated proof by root after retries: %w", err)
+	}
+
+	if proof.ID == "" {
+		return nil, sql.ErrNoRows
+	}
+
+	return proof, nil
+}
+
+// GetSuperProofContainingRoot fetches the earliest super proof whose leaves include the given aggregate root
+func GetSuperProofContainingRoot(root string) (*AggregatedProof, error) {
+	proof := new(AggregatedProof)
+
+	err := RetryDBOperation(func() error {
+		db, err := GetDB()
+		if err != nil {
+			return fmt.Errorf("failed to get database connection: %w", err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		err = db.NewSelect().
+			Model(proof).
+			Where("btc_tx_hash IS NOT NULL").
+			Where("? = ANY(proofs)", root).
+			Order("timestamp ASC").
+			Limit(1).
+			Scan(ctx)
+
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return nil
+			}
+			return fmt.Errorf("failed to fetch super proof containing root: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get super proof containing root after retries: %w", err)
+	}
+
+	if proof.ID == "" {
+		return nil, sql.ErrNoRows
+	}
+
+	return proof, nil
+}
+
+// GetUnconfirmedSuperProofs returns anchored super proofs that have not yet reached the required confirmations
+func GetUnconfirmedSuperProofs() ([]AggregatedProof, error) {
+	var proofs []AggregatedProof
+
+	err := RetryDBOperation(func() error {
+		db, err := GetDB()
+		if err != nil {
+			return fmt.Errorf("failed to get database connection: %w", err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		err = db.NewSelect().
+			Model(&proofs).
+			Where("btc_tx_hash IS NOT NULL").
+			Where("btc_tx_hash != ''").
+			Where("btc_confirmed_at IS NULL").
+			Order("timestamp ASC").
+			Scan(ctx)
+
+		if err != nil {
+			return fmt.Errorf("failed to fetch unconfirmed super proofs: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get unconfirmed super proofs after retries: %w", err)
+	}
+
+	return proofs, nil
+}
+
+// GetSuperProofs returns a page of super proofs, newest first
+func GetSuperProofs(limit int, offset int) ([]AggregatedProof, error) {
+	var proofs []AggregatedProof
+
+	err := RetryDBOperation(func() error {
+		db, err := GetDB()
+		if err != nil {
+			return fmt.Errorf("failed to get database connection: %w", err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		err = db.NewSelect().
+			Model(&proofs).
+			Where("btc_tx_hash IS NOT NULL").
+			Order("timestamp DESC").
+			Limit(limit).
+			Offset(offset).
+			Scan(ctx)
+
+		if err != nil {
+			return fmt.Errorf("failed to fetch super proofs: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get super proofs after retries: %w", err)
+	}
+
+	return proofs, nil
+}
+
+// GetAggregatedProofsByNamespace returns a page of the aggregates holding at
+// least one proof of namespace, newest first
+func GetAggregatedProofsByNamespace(namespace string, limit int, offset int) ([]AggregatedProof, error) {
+	var proofs []AggregatedProof
+
+	err := RetryDBOperation(func() error {
+		db, err := GetDB()
+		if err != nil {
+			return fmt.Errorf("failed to get database connection: %w", err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		roots := db.NewSelect().
+			Model((*PendingProof)(nil)).
+			ColumnExpr("DISTINCT aggregate_root").
+			Where("namespace = ?", namespace).
+			Where("aggregate_root IS NOT NULL")
+
+		err = db.NewSelect().
+			Model(&proofs).
+			Where("merkle_root IN (?)", roots).
+			Order("timestamp DESC").
+			Limit(limit).
+			Offset(offset).
+			Scan(ctx)
+
+		if err != nil {
+			return fmt.Errorf("failed to fetch aggregated proofs by namespace: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get aggregated proofs by namespace after retries: %w", err)
+	}
+
+	return proofs, nil
+}
+
+// UpdateSuperProofConfirmation records the block an anchor was mined in. A nil block hash clears
+// the block data, e.g. after a reorg pushed the transaction back into the mempool.
+func UpdateSuperProofConfirmation(id string, block_hash *string, block_number *int64, block_time *time.Time, confirmations int64, confirmed bool) error {
+	var confirmedAt *time.Time
+	if confirmed {
+		now := time.Now().UTC()
+		confirmedAt = &now
+	}
+
+	err := RetryDBOperation(func() error {
+		db, err := GetDB()
+		if err != nil {
+			return fmt.Errorf("failed to get database connection: %w", err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		_, err = db.NewUpdate().
+			Model(&AggregatedProof{}).
+			Where("id = ?", id).
+			Set("btc_block_hash = ?", block_hash).
+			Set("btc_block_number = ?", block_number).
+			Set("btc_block_time = ?", block_time).
+			Set("btc_confirmations = ?", confirmations).
+			Set("btc_confirmed_at = ?", confirmedAt).
+			Exec(ctx)
+
+		if err != nil {
+			return fmt.Errorf("failed to update super proof confirmation: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to update super proof confirmation after retries: %w", err)
+	}
+
+	return nil
+}
+
+// RequeueSuperProofForAnchoring clears the BTC anchor of a super proof so that the
+// NonBTCTxSuperProofCronJob picks it up again
+func RequeueSuperProofForAnchoring(id string) error {
+	err := RetryDBOperation(func() error {
+		db, err := GetDB()
+		if err != nil {
+			return fmt.Errorf("failed to get database connection: %w", err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		_, err = db.NewUpdate().
+			Model(&AggregatedProof{}).
+			Where("id = ?", id).
+			Set("btc_tx_hash = ''").
+			Set("btc_block_hash = NULL").
+			Set("btc_block_number = NULL").
+			Set("btc_block_time = NULL").
+			Set("btc_confirmations = 0").
+			Set("btc_confirmed_at = NULL").
+			Exec(ctx)
+
+		if err != nil {
+			return fmt.Errorf("failed to requeue super proof: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to requeue super proof after retries: %w", err)
+	}
+
+	log.Printf("Requeued super proof for BTC anchoring: %s", id)
+	return nil
+}
@@ -0,0 +1,225 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// BTCAnchor is a protocol-id tagged OP_RETURN output observed on Bitcoin by the reader service
+type BTCAnchor struct {
+	bun.BaseModel `bun:"table:btc_anchors,alias:ba"`
+
+	ID            int64     `bun:"id,pk,autoincrement"`
+	TxHash        string    `bun:"tx_hash,type:varchar(64),notnull,unique:btc_anchor_output"`
+	Vout          int       `bun:"vout,notnull,unique:btc_anchor_output"`
+	BlockHash     string    `bun:"block_hash,type:varchar(64),notnull"`
+	BlockHeight   int64     `bun:"block_height,notnull"`
+	BlockTime     time.Time `bun:"block_time,notnull"`
+	Confirmations int64     `bun:"confirmations,notnull,default:0"`
+	Payload       []byte    `bun:"payload,type:bytea,notnull"`
+	Root          string    `bun:"root,type:varchar(255),notnull"`
+	CreatedAt     time.Time `bun:"created_at,notnull,default:current_timestamp"`
+	UpdatedAt     time.Time `bun:"updated_at,notnull,default:current_timestamp"`
+}
+
+// UpsertBTCAnchor records an anchor, refreshing block and confirmation data if it was already seen
+func UpsertBTCAnchor(anchor *BTCAnchor) error {
+	anchor.UpdatedAt = time.Now().UTC()
+
+	err := RetryDBOperation(func() error {
+		db, err := GetDB()
+		if err != nil {
+			return fmt.Errorf("failed to get database connection: %w", err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		_, err = db.NewInsert().
+			Model(anchor).
+			On("CONFLICT (tx_hash, vout) DO UPDATE").
+			Set("block_hash = EXCLUDED.block_hash").
+			Set("block_height = EXCLUDED.block_height").
+			Set("block_time = EXCLUDED.block_time").
+			Set("confirmations = EXCLUDED.confirmations").
+			Set("updated_at = EXCLUDED.updated_at").
+			Exec(ctx)
+		if err != nil {
+			return fmt.Errorf("upsert operation failed: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to store BTC anchor after retries: %w", err)
+	}
+
+	log.Printf("Stored BTC anchor %s:%d at height %d (root %s)", anchor.TxHash, anchor.Vout, anchor.BlockHeight, anchor.Root)
+	return nil
+}
+
+// GetBTCAnchorsByRoot returns every anchor observed for the given root in the active chain, most recent first
+func GetBTCAnchorsByRoot(root string) ([]BTCAnchor, error) {
+	var anchors []BTCAnchor
+
+	err := RetryDBOperation(func() error {
+		db, err := GetDB()
+		if err != nil {
+			return fmt.Errorf("failed to get database connection: %w", err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		err = db.NewSelect().
+			Model(&anchors).
+			Where("root = ?", root).
+			Where("confirmations >= 0").
+			Order("block_height DESC").
+			Scan(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to fetch BTC anchors: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get BTC anchors by root after retries: %w", err)
+	}
+
+	return anchors, nil
+}
+
+// GetBTCAnchorsByPayloadPrefix returns every anchor whose payload starts with prefix
+func GetBTCAnchorsByPayloadPrefix(prefix []byte) ([]BTCAnchor, error) {
+	var anchors []BTCAnchor
+
+	err := RetryDBOperation(func() error {
+		db, err := GetDB()
+		if err != nil {
+			return fmt.Errorf("failed to get database connection: %w", err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		err = db.NewSelect().
+			Model(&anchors).
+			Where("position(? in payload) = 1", prefix).
+			Order("block_height ASC").
+			Scan(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to fetch BTC anchors: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get BTC anchors by payload prefix after retries: %w", err)
+	}
+
+	return anchors, nil
+}
+
+// SetBTCAnchorRootByPayloadPrefix records the root of a reassembled split payload on all of its parts
+func SetBTCAnchorRootByPayloadPrefix(prefix []byte, root string) error {
+	err := RetryDBOperation(func() error {
+		db, err := GetDB()
+		if err != nil {
+			return fmt.Errorf("failed to get database connection: %w", err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		_, err = db.NewUpdate().
+			Model((*BTCAnchor)(nil)).
+			Set("root = ?", root).
+			Set("updated_at = ?", time.Now().UTC()).
+			Where("position(? in payload) = 1", prefix).
+			Exec(ctx)
+		if err != nil {
+			return fmt.Errorf("update operation failed: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to set BTC anchor root after retries: %w", err)
+	}
+
+	return nil
+}
+
+// GetUnsettledBTCAnchors returns the anchors in the active chain with fewer than depth confirmations
+func GetUnsettledBTCAnchors(depth int64) ([]BTCAnchor, error) {
+	var anchors []BTCAnchor
+
+	err := RetryDBOperation(func() error {
+		db, err := GetDB()
+		if err != nil {
+			return fmt.Errorf("failed to get database connection: %w", err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		err = db.NewSelect().
+			Model(&anchors).
+			Where("confirmations >= 0").
+			Where("confirmations < ?", depth).
+			Order("block_height ASC").
+			Scan(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to fetch BTC anchors: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get unsettled BTC anchors after retries: %w", err)
+	}
+
+	return anchors, nil
+}
+
+// SetBTCAnchorConfirmations records the confirmations of every anchor in
+// block_hash. -1 marks anchors whose block left the active chain.
+func SetBTCAnchorConfirmations(block_hash string, confirmations int64) error {
+	err := RetryDBOperation(func() error {
+		db, err := GetDB()
+		if err != nil {
+			return fmt.Errorf("failed to get database connection: %w", err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		_, err = db.NewUpdate().
+			Model((*BTCAnchor)(nil)).
+			Set("confirmations = ?", confirmations).
+			Set("updated_at = ?", time.Now().UTC()).
+			Where("block_hash = ?", block_hash).
+			Exec(ctx)
+		if err != nil {
+			return fmt.Errorf("update operation failed: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to set BTC anchor confirmations after retries: %w", err)
+	}
+
+	return nil
+}
@@ -78,6 +78,13 @@ func InitDBWithConfig(config DatabaseConfig) error {
 	// Create a new Bun DB instance with PostgreSQL dialect
 	DB = bun.NewDB(sqldb, pgdialect.New())
 
+	// Create tables owned by this service
+	if err := createTables(ctx); err != nil {
+		DB.Close()
+		DB = nil
+		return err
+	}
+
 	// Start health check
 	startHealthCheck()
 
@@ -85,6 +92,58 @@ func InitDBWithConfig(config DatabaseConfig) error {
 	return nil
 }
 
+// managedModels are the tables this service creates on startup. The
+// aggregated_proofs table is provisioned externally and is not listed here.
+var managedModels = []interface{}{
+	(*BTCAnchor)(nil),
+	(*PendingProof)(nil),
+	(*SubmitterKey)(nil),
+}
+
+// schemaMigrations add columns this service needs to externally provisioned
+// tables, and indexes bun cannot declare on managed ones
+var schemaMigrations = []string{
+	`ALTER TABLE aggregated_proofs ADD COLUMN IF NOT EXISTS btc_block_hash varchar(64)`,
+	`ALTER TABLE aggregated_proofs ADD COLUMN IF NOT EXISTS btc_block_time timestamptz`,
+	`ALTER TABLE aggregated_proofs ADD COLUMN IF NOT EXISTS btc_confirmations bigint NOT NULL DEFAULT 0`,
+	`ALTER TABLE aggregated_proofs ADD COLUMN IF NOT EXISTS btc_confirmed_at timestamptz`,
+	`CREATE INDEX IF NOT EXISTS pending_proofs_unconsumed_idx ON pending_proofs (id) WHERE consumed_at IS NULL`,
+	`CREATE INDEX IF NOT EXISTS pending_proofs_leaf_hash_idx ON pending_proofs (leaf_hash)`,
+	`ALTER TABLE pending_proofs ADD COLUMN IF NOT EXISTS namespace varchar(64) NOT NULL DEFAULT ''`,
+	`ALTER TABLE pending_proofs ADD COLUMN IF NOT EXISTS leaf_index bigint`,
+	`CREATE INDEX IF NOT EXISTS pending_proofs_namespace_idx ON pending_proofs (namespace, id)`,
+	`CREATE INDEX IF NOT EXISTS pending_proofs_aggregate_root_idx ON pending_proofs (aggregate_root)`,
+	`ALTER TABLE pending_proofs ADD COLUMN IF NOT EXISTS proof_type varchar(64) NOT NULL DEFAULT ''`,
+	`ALTER TABLE pending_proofs ADD COLUMN IF NOT EXISTS verification varchar(16) NOT NULL DEFAULT ''`,
+	`ALTER TABLE pending_proofs ADD COLUMN IF NOT EXISTS verifying_key_hash varchar(66)`,
+	`ALTER TABLE pending_proofs ADD COLUMN IF NOT EXISTS verified_at timestamptz`,
+	`ALTER TABLE aggregated_proofs ADD COLUMN IF NOT EXISTS merkle_root varchar(255)`,
+	`ALTER TABLE aggregated_proofs ADD COLUMN IF NOT EXISTS backend varchar(16) NOT NULL DEFAULT 'merkle'`,
+	`ALTER TABLE aggregated_proofs ADD COLUMN IF NOT EXISTS public_inputs bytea`,
+	// aggregate_proof held the root of every aggregate before snark aggregation
+	`UPDATE aggregated_proofs SET merkle_root = convert_from(aggregate_proof, 'UTF8') WHERE merkle_root IS NULL AND backend = 'merkle'`,
+	`CREATE INDEX IF NOT EXISTS aggregated_proofs_merkle_root_idx ON aggregated_proofs (merkle_root)`,
+	`ALTER TABLE pending_proofs ADD COLUMN IF NOT EXISTS batch_id varchar(32)`,
+	`ALTER TABLE pending_proofs ADD COLUMN IF NOT EXISTS discarded varchar(16) NOT NULL DEFAULT ''`,
+}
+
+// createTables creates any missing tables for managedModels and applies schemaMigrations
+func createTables(ctx context.Context) error {
+	for _, model := range managedModels {
+		_, err := DB.NewCreateTable().Model(model).IfNotExists().Exec(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to create table for %T: %w", model, err)
+		}
+	}
+
+	for _, migration := range schemaMigrations {
+		if _, err := DB.ExecContext(ctx, migration); err != nil {
+			return fmt.Errorf("failed to apply migration %q: %w", migration, err)
+		}
+	}
+	return nil
+}
+
 // startHealthCheck starts a periodic health check for the database
 func startHealthCheck() {
 	if healthCheck != nil {
@@ -0,0 +1,352 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+)
+
+// PendingProof is a proof received on the data socket, persisted before it
+// is acknowledged and consumed once it has been aggregated and stored
+type PendingProof struct {
+	bun.BaseModel `bun:"table:pending_proofs,alias:pp"`
+
+	ID            int64      `bun:"id,pk,autoincrement"`
+	Namespace     string     `bun:"namespace,type:varchar(64),notnull,default:''"`
+	Label         string     `bun:"label,type:varchar(255),notnull"`
+	ProofType     string     `bun:"proof_type,type:varchar(64),notnull,default:''"`
+	Proof         []byte     `bun:"proof,type:bytea,notnull"`
+	LeafHash      string     `bun:"leaf_hash,type:varchar(66),notnull"`
+	ReceivedAt    time.Time  `bun:"received_at,notnull,default:current_timestamp"`
+	ConsumedAt    *time.Time `bun:"consumed_at"`
+	AggregateRoot *string    `bun:"aggregate_root,type:varchar(255)"`
+	LeafIndex     *int       `bun:"leaf_index"`                                    // position of the leaf in its aggregate
+	BatchID       *string    `bun:"batch_id,type:varchar(32)"`                     // batch the proof was sealed in, nil while its batch is open
+	Discarded     string     `bun:"discarded,type:varchar(16),notnull,default:''"` // why the proof was consumed without being aggregated
+
+	Verification     string     `bun:"verification,type:varchar(16),notnull,default:''"` // Groth16 verification outcome, empty when not checked
+	VerifyingKeyHash *string    `bun:"verifying_key_hash,type:varchar(66)"`
+	VerifiedAt       *time.Time `bun:"verified_at"`
+}
+
+// CreatePendingProof durably records a received proof with its verification
+// outcome. verifying_key_hash is empty unless the proof was verified.
+func CreatePendingProof(namespace string, label string, proof_type string, proof []byte, leaf_hash string, verification string, verifying_key_hash string) (*PendingProof, error) {
+	pp := &PendingProof{
+		Namespace:    namespace,
+		Label:        label,
+		ProofType:    proof_type,
+		Proof:        proof,
+		LeafHash:     leaf_hash,
+		ReceivedAt:   time.Now().UTC(),
+		Verification: verification,
+	}
+	if verifying_key_hash != "" {
+		pp.VerifyingKeyHash = &verifying_key_hash
+		pp.VerifiedAt = &pp.ReceivedAt
+	}
+
+	err := RetryDBOperation(func() error {
+		db, err := GetDB()
+		if err != nil {
+			return fmt.Errorf("failed to get database connection: %w", err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		_, err = db.NewInsert().Model(pp).Returning("id").Exec(ctx)
+		if err != nil {
+			return fmt.Errorf("insert operation failed: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to create pending proof after retries: %w", err)
+	}
+
+	return pp, nil
+}
+
+// GetUnconsumedPendingProofs returns every proof not yet aggregated, oldest first
+func GetUnconsumedPendingProofs() ([]PendingProof, error) {
+	var proofs []PendingProof
+
+	err := RetryDBOperation(func() error {
+		db, err := GetDB()
+		if err != nil {
+			return fmt.Errorf("failed to get database connection: %w", err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		err = db.NewSelect().
+			Model(&proofs).
+			Where("consumed_at IS NULL").
+			Order("id ASC").
+			Scan(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to fetch pending proofs: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get unconsumed pending proofs after retries: %w", err)
+	}
+
+	return proofs, nil
+}
+
+// GetPendingProofByLeafHash fetches the most recent submission of leaf_hash,
+// preferring submissions that were not discarded
+func GetPendingProofByLeafHash(leaf_hash string) (*PendingProof, error) {
+	proof := new(PendingProof)
+
+	err := RetryDBOperation(func() error {
+		db, err := GetDB()
+		if err != nil {
+			return fmt.Errorf("failed to get database connection: %w", err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		err = db.NewSelect().
+			Model(proof).
+			Where("leaf_hash = ?", leaf_hash).
+			OrderExpr("(discarded = '') DESC, id DESC").
+			Limit(1).
+			Scan(ctx)
+
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return nil
+			}
+			return fmt.Errorf("failed to fetch pending proof by leaf hash: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending proof by leaf hash after retries: %w", err)
+	}
+
+	if proof.ID == 0 {
+		return nil, sql.ErrNoRows
+	}
+
+	return proof, nil
+}
+
+// GetPendingProofsByNamespace returns a page of the proofs submitted to namespace, newest first
+func GetPendingProofsByNamespace(namespace string, limit int, offset int) ([]PendingProof, error) {
+	var proofs []PendingProof
+
+	err := RetryDBOperation(func() error {
+		db, err := GetDB()
+		if err != nil {
+			return fmt.Errorf("failed to get database connection: %w", err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		err = db.NewSelect().
+			Model(&proofs).
+			Where("namespace = ?", namespace).
+			Order("id DESC").
+			Limit(limit).
+			Offset(offset).
+			Scan(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to fetch pending proofs by namespace: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending proofs by namespace after retries: %w", err)
+	}
+
+	return proofs, nil
+}
+
+// GetPendingProofsByAggregateRoot returns the proofs aggregated under aggregate_root in leaf order
+func GetPendingProofsByAggregateRoot(aggregate_root string) ([]PendingProof, error) {
+	var proofs []PendingProof
+
+	err := RetryDBOperation(func() error {
+		db, err := GetDB()
+		if err != nil {
+			return fmt.Errorf("failed to get database connection: %w", err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		err = db.NewSelect().
+			Model(&proofs).
+			Where("aggregate_root = ?", aggregate_root).
+			Order("leaf_index ASC", "id ASC").
+			Scan(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to fetch pending proofs by aggregate root: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending proofs by aggregate root after retries: %w", err)
+	}
+
+	return proofs, nil
+}
+
+// UpdatePendingProofVerification replaces the verification outcome of a
+// proof verified again. verifying_key_hash is empty unless it verified.
+func UpdatePendingProofVerification(id int64, verification string, verifying_key_hash string) error {
+	var keyHash *string
+	var verifiedAt *time.Time
+	if verifying_key_hash != "" {
+		now := time.Now().UTC()
+		keyHash, verifiedAt = &verifying_key_hash, &now
+	}
+
+	err := RetryDBOperation(func() error {
+		db, err := GetDB()
+		if err != nil {
+			return fmt.Errorf("failed to get database connection: %w", err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		_, err = db.NewUpdate().
+			Model((*PendingProof)(nil)).
+			Set("verification = ?", verification).
+			Set("verifying_key_hash = ?", keyHash).
+			Set("verified_at = ?", verifiedAt).
+			Where("id = ?", id).
+			Exec(ctx)
+		if err != nil {
+			return fmt.Errorf("update operation failed: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to update pending proof verification after retries: %w", err)
+	}
+
+	return nil
+}
+
+// DiscardPendingProofs consumes proofs that will never be aggregated, so
+// they are not restored again, recording the reason
+func DiscardPendingProofs(ids []int64, reason string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	err := RetryDBOperation(func() error {
+		db, err := GetDB()
+		if err != nil {
+			return fmt.Errorf("failed to get database connection: %w", err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		_, err = db.NewUpdate().
+			Model((*PendingProof)(nil)).
+			Set("consumed_at = ?", time.Now().UTC()).
+			Set("discarded = ?", reason).
+			Where("id IN (?)", bun.In(ids)).
+			Where("consumed_at IS NULL").
+			Exec(ctx)
+		if err != nil {
+			return fmt.Errorf("update operation failed: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to discard pending proofs after retries: %w", err)
+	}
+
+	log.Printf("Discarded %d pending proofs as %s", len(ids), reason)
+	return nil
+}
+
+// AssignPendingProofsToBatch records that the given proofs were sealed in
+// batch_id, so the batch is published as sealed after a restart
+func AssignPendingProofsToBatch(ids []int64, batch_id string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	err := RetryDBOperation(func() error {
+		db, err := GetDB()
+		if err != nil {
+			return fmt.Errorf("failed to get database connection: %w", err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		_, err = db.NewUpdate().
+			Model((*PendingProof)(nil)).
+			Set("batch_id = ?", batch_id).
+			Where("id IN (?)", bun.In(ids)).
+			Where("consumed_at IS NULL").
+			Exec(ctx)
+		if err != nil {
+			return fmt.Errorf("update operation failed: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to assign pending proofs to batch after retries: %w", err)
+	}
+
+	return nil
+}
+
+// markPendingProofsConsumed records on db, which may be a transaction, that
+// the given proofs were aggregated under aggregate_root. ids must be in leaf
+// order: each proof's position in ids is stored as its leaf index.
+func markPendingProofsConsumed(ctx context.Context, db bun.IDB, ids []int64, aggregate_root string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	_, err := db.NewUpdate().
+		Model((*PendingProof)(nil)).
+		Set("consumed_at = ?", time.Now().UTC()).
+		Set("aggregate_root = ?", aggregate_root).
+		Set("leaf_index = array_position(?::bigint[], id) - 1", pgdialect.Array(ids)).
+		Where("id IN (?)", bun.In(ids)).
+		Where("consumed_at IS NULL").
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("update operation failed: %w", err)
+	}
+	return nil
+}
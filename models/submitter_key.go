@@ -0,0 +1,66 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// SubmitterKey is a public key authorised to submit proofs, in addition to
+// the keys listed under submitter-auth in the config
+type SubmitterKey struct {
+	bun.BaseModel `bun:"table:submitter_keys,alias:sk"`
+
+	ID         int64     `bun:"id,pk,autoincrement"`
+	Name       string    `bun:"name,type:varchar(255),notnull"`
+	Scheme     string    `bun:"scheme,type:varchar(16),notnull,unique:submitter_key"`      // secp256k1 | ed25519
+	PublicKey  string    `bun:"public_key,type:varchar(130),notnull,unique:submitter_key"` // lowercase hex, compressed for secp256k1
+	Namespaces []string  `bun:"namespaces,array,type:text[],notnull,default:'{}'"`         // empty allows every namespace
+	Revoked    bool      `bun:"revoked,notnull,default:false"`
+	CreatedAt  time.Time `bun:"created_at,notnull,default:current_timestamp"`
+}
+
+// GetSubmitterKey fetches the unrevoked key with the given scheme and public key
+func GetSubmitterKey(scheme string, public_key string) (*SubmitterKey, error) {
+	key := new(SubmitterKey)
+
+	err := RetryDBOperation(func() error {
+		db, err := GetDB()
+		if err != nil {
+			return fmt.Errorf("failed to get database connection: %w", err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		err = db.NewSelect().
+			Model(key).
+			Where("scheme = ?", scheme).
+			Where("public_key = ?", public_key).
+			Where("revoked = false").
+			Limit(1).
+			Scan(ctx)
+
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return nil
+			}
+			return fmt.Errorf("failed to fetch submitter key: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get submitter key after retries: %w", err)
+	}
+
+	if key.ID == 0 {
+		return nil, sql.ErrNoRows
+	}
+
+	return key, nil
+}
@@ -0,0 +1,314 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.4.0
+// - protoc             (unknown)
+// source: aggregation/v1/aggregation.proto
+
+package aggregationv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.62.0 or later.
+const _ = grpc.SupportPackageIsVersion8
+
+const (
+	AggregationService_SubmitProof_FullMethodName       = "/aggregation.v1.AggregationService/SubmitProof"
+	AggregationService_SubmitBatch_FullMethodName       = "/aggregation.v1.AggregationService/SubmitBatch"
+	AggregationService_WatchProof_FullMethodName        = "/aggregation.v1.AggregationService/WatchProof"
+	AggregationService_GetInclusionProof_FullMethodName = "/aggregation.v1.AggregationService/GetInclusionProof"
+	AggregationService_VerifyAnchor_FullMethodName      = "/aggregation.v1.AggregationService/VerifyAnchor"
+)
+
+// AggregationServiceClient is the client API for AggregationService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// AggregationService submits proofs to the aggregation layer and follows them
+// until their super proof is confirmed on Bitcoin. Submissions go through the
+// same pipeline as the ZMQ data socket.
+type AggregationServiceClient interface {
+	// SubmitProof queues one proof for the current aggregation window
+	SubmitProof(ctx context.Context, in *SubmitProofRequest, opts ...grpc.CallOption) (*SubmitProofResponse, error)
+	// SubmitBatch queues several proofs, each acknowledged on its own
+	SubmitBatch(ctx context.Context, in *SubmitBatchRequest, opts ...grpc.CallOption) (*SubmitBatchResponse, error)
+	// WatchProof streams the proof's status every time its stage changes and
+	// ends once it is confirmed on Bitcoin or rejected
+	WatchProof(ctx context.Context, in *WatchProofRequest, opts ...grpc.CallOption) (AggregationService_WatchProofClient, error)
+	// GetInclusionProof returns the path from the proof's leaf to its
+	// aggregate root and, once rolled up, to the super proof root
+	GetInclusionProof(ctx context.Context, in *GetInclusionProofRequest, opts ...grpc.CallOption) (*GetInclusionProofResponse, error)
+	// VerifyAnchor checks an inclusion proof and that its super proof root is
+	// anchored on Bitcoin
+	VerifyAnchor(ctx context.Context, in *VerifyAnchorRequest, opts ...grpc.CallOption) (*VerifyAnchorResponse, error)
+}
+
+type aggregationServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAggregationServiceClient(cc grpc.ClientConnInterface) AggregationServiceClient {
+	return &aggregationServiceClient{cc}
+}
+
+func (c *aggregationServiceClient) SubmitProof(ctx context.Context, in *SubmitProofRequest, opts ...grpc.CallOption) (*SubmitProofResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SubmitProofResponse)
+	err := c.cc.Invoke(ctx, AggregationService_SubmitProof_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aggregationServiceClient) SubmitBatch(ctx context.Context, in *SubmitBatchRequest, opts ...grpc.CallOption) (*SubmitBatchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SubmitBatchResponse)
+	err := c.cc.Invoke(ctx, AggregationService_SubmitBatch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aggregationServiceClient) WatchProof(ctx context.Context, in *WatchProofRequest, opts ...grpc.CallOption) (AggregationService_WatchProofClient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &AggregationService_ServiceDesc.Streams[0], AggregationService_WatchProof_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &aggregationServiceWatchProofClient{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type AggregationService_WatchProofClient interface {
+	Recv() (*ProofStatus, error)
+	grpc.ClientStream
+}
+
+type aggregationServiceWatchProofClient struct {
+	grpc.ClientStream
+}
+
+func (x *aggregationServiceWatchProofClient) Recv() (*ProofStatus, error) {
+	m := new(ProofStatus)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *aggregationServiceClient) GetInclusionProof(ctx context.Context, in *GetInclusionProofRequest, opts ...grpc.CallOption) (*GetInclusionProofResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetInclusionProofResponse)
+	err := c.cc.Invoke(ctx, AggregationService_GetInclusionProof_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aggregationServiceClient) VerifyAnchor(ctx context.Context, in *VerifyAnchorRequest, opts ...grpc.CallOption) (*VerifyAnchorResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(VerifyAnchorResponse)
+	err := c.cc.Invoke(ctx, AggregationService_VerifyAnchor_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AggregationServiceServer is the server API for AggregationService service.
+// All implementations must embed UnimplementedAggregationServiceServer
+// for forward compatibility
+//
+// AggregationService submits proofs to the aggregation layer and follows them
+// until their super proof is confirmed on Bitcoin. Submissions go through the
+// same pipeline as the ZMQ data socket.
+type AggregationServiceServer interface {
+	// SubmitProof queues one proof for the current aggregation window
+	SubmitProof(context.Context, *SubmitProofRequest) (*SubmitProofResponse, error)
+	// SubmitBatch queues several proofs, each acknowledged on its own
+	SubmitBatch(context.Context, *SubmitBatchRequest) (*SubmitBatchResponse, error)
+	// WatchProof streams the proof's status every time its stage changes and
+	// ends once it is confirmed on Bitcoin or rejected
+	WatchProof(*WatchProofRequest, AggregationService_WatchProofServer) error
+	// GetInclusionProof returns the path from the proof's leaf to its
+	// aggregate root and, once rolled up, to the super proof root
+	GetInclusionProof(context.Context, *GetInclusionProofRequest) (*GetInclusionProofResponse, error)
+	// VerifyAnchor checks an inclusion proof and that its super proof root is
+	// anchored on Bitcoin
+	VerifyAnchor(context.Context, *VerifyAnchorRequest) (*VerifyAnchorResponse, error)
+	mustEmbedUnimplementedAggregationServiceServer()
+}
+
+// UnimplementedAggregationServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedAggregationServiceServer struct {
+}
+
+func (UnimplementedAggregationServiceServer) SubmitProof(context.Context, *SubmitProofRequest) (*SubmitProofResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubmitProof not implemented")
+}
+func (UnimplementedAggregationServiceServer) SubmitBatch(context.Context, *SubmitBatchRequest) (*SubmitBatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubmitBatch not implemented")
+}
+func (UnimplementedAggregationServiceServer) WatchProof(*WatchProofRequest, AggregationService_WatchProofServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchProof not implemented")
+}
+func (UnimplementedAggregationServiceServer) GetInclusionProof(context.Context, *GetInclusionProofRequest) (*GetInclusionProofResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetInclusionProof not implemented")
+}
+func (UnimplementedAggregationServiceServer) VerifyAnchor(context.Context, *VerifyAnchorRequest) (*VerifyAnchorResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifyAnchor not implemented")
+}
+func (UnimplementedAggregationServiceServer) mustEmbedUnimplementedAggregationServiceServer() {}
+
+// UnsafeAggregationServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AggregationServiceServer will
+// result in compilation errors.
+type UnsafeAggregationServiceServer interface {
+	mustEmbedUnimplementedAggregationServiceServer()
+}
+
+func RegisterAggregationServiceServer(s grpc.ServiceRegistrar, srv AggregationServiceServer) {
+	s.RegisterService(&AggregationService_ServiceDesc, srv)
+}
+
+func _AggregationService_SubmitProof_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubmitProofRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AggregationServiceServer).SubmitProof(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AggregationService_SubmitProof_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AggregationServiceServer).SubmitProof(ctx, req.(*SubmitProofRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AggregationService_SubmitBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubmitBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AggregationServiceServer).SubmitBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AggregationService_SubmitBatch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AggregationServiceServer).SubmitBatch(ctx, req.(*SubmitBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AggregationService_WatchProof_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchProofRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AggregationServiceServer).WatchProof(m, &aggregationServiceWatchProofServer{ServerStream: stream})
+}
+
+type AggregationService_WatchProofServer interface {
+	Send(*ProofStatus) error
+	grpc.ServerStream
+}
+
+type aggregationServiceWatchProofServer struct {
+	grpc.ServerStream
+}
+
+func (x *aggregationServiceWatchProofServer) Send(m *ProofStatus) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _AggregationService_GetInclusionProof_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetInclusionProofRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AggregationServiceServer).GetInclusionProof(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AggregationService_GetInclusionProof_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AggregationServiceServer).GetInclusionProof(ctx, req.(*GetInclusionProofRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AggregationService_VerifyAnchor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyAnchorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AggregationServiceServer).VerifyAnchor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AggregationService_VerifyAnchor_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AggregationServiceServer).VerifyAnchor(ctx, req.(*VerifyAnchorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// AggregationService_ServiceDesc is the grpc.ServiceDesc for AggregationService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AggregationService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "aggregation.v1.AggregationService",
+	HandlerType: (*AggregationServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SubmitProof",
+			Handler:    _AggregationService_SubmitProof_Handler,
+		},
+		{
+			MethodName: "SubmitBatch",
+			Handler:    _AggregationService_SubmitBatch_Handler,
+		},
+		{
+			MethodName: "GetInclusionProof",
+			Handler:    _AggregationService_GetInclusionProof_Handler,
+		},
+		{
+			MethodName: "VerifyAnchor",
+			Handler:    _AggregationService_VerifyAnchor_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchProof",
+			Handler:       _AggregationService_WatchProof_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "aggregation/v1/aggregation.proto",
+}
@@ -0,0 +1,18 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/Layer-Edge/bitcoin-da/da"
+)
+
+// Prints a CURVE key pair for data-block-curve or a data socket client
+func main() {
+	publicKey, secretKey, err := da.GenerateCurveKeyPair()
+	if err != nil {
+		log.Fatal("Failed to generate CURVE key pair: ", err)
+	}
+	fmt.Printf("public-key: %s\n", publicKey)
+	fmt.Printf("secret-key: %s\n", secretKey)
+}
@@ -35,6 +35,6 @@ func main() {
 	fmt.Printf("Response received %s\n", &proofs)
 	fmt.Printf("BTC TX: %s\n", string(*proofs[0].BTCTxHash))
 	fmt.Printf("Edgen Chain TX: %s\n", string(proofs[0].TransactionHash))
-	fmt.Printf("Aggregated Proof: %s\n", string(proofs[0].AggregateProof))
+	fmt.Printf("Aggregated Proof: %s\n", proofs[0].MerkleRoot)
 	fmt.Printf("Aggregated Proof Timestamp: %s\n", proofs[0].Timestamp.Format("2006-01-02 15:04:05"))
 }
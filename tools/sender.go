@@ -1,26 +1,89 @@
 package main
 
 import (
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"log"
+	"os"
+	"strings"
 	"time"
 
+	"github.com/Layer-Edge/bitcoin-da/da"
 	"gopkg.in/zeromq/goczmq.v4"
 )
 
 func main() {
 	ep := "tcp://0.0.0.0:40006"
-	sender := goczmq.NewReqChanneler(ep)
-	if sender == nil {
-		log.Fatal("Failed to subscribe to endpoint: ", ep)
+	var sendChan chan<- [][]byte
+	var recvChan <-chan [][]byte
+	// Connect with CURVE when the writer runs with data-block-curve
+	if serverKey := os.Getenv("DATA_BLOCK_SERVER_KEY"); serverKey != "" {
+		sender, err := da.NewCurveReqChanneler(ep, serverKey, os.Getenv("DATA_BLOCK_CLIENT_SECRET_KEY"))
+		if err != nil {
+			log.Fatal("Failed to connect to endpoint: ", err)
+		}
+		defer sender.Destroy()
+		sendChan, recvChan = sender.SendChan, sender.RecvChan
+	} else {
+		sender := goczmq.NewReqChanneler(ep)
+		if sender == nil {
+			log.Fatal("Failed to subscribe to endpoint: ", ep)
+		}
+		defer sender.Destroy()
+		sendChan, recvChan = sender.SendChan, sender.RecvChan
 	}
-	defer sender.Destroy()
 	// Let the socket connect
 	time.Sleep(5 * time.Second)
 	// Send some data
 	data := [][]byte{[]byte("datablock"), []byte("Test Data " + time.Now().String()), []byte("!!!!!")}
-	sender.SendChan <- data
+	// Sign the submission when a secp256k1 key is given, for writers
+	// running with submitter-auth
+	if key := os.Getenv("SUBMITTER_PRIVATE_KEY"); key != "" {
+		privKey, err := hex.DecodeString(strings.TrimPrefix(key, "0x"))
+		if err != nil {
+			log.Fatal("Invalid SUBMITTER_PRIVATE_KEY: ", err)
+		}
+		data[2], err = da.SignSubmission(da.SignatureSchemeSecp256k1, privKey, da.NamespaceFromLabel(string(data[0])), data[1])
+		if err != nil {
+			log.Fatal("Failed to sign submission: ", err)
+		}
+	}
+	// Name the proof type for writers running with proof-validation
+	if proofType := os.Getenv("PROOF_TYPE"); proofType != "" {
+		data = append(data, []byte(proofType))
+	}
+	sendChan <- data
 	fmt.Printf("Data sent %s\n", data)
-	resp := <-sender.RecvChan
-	fmt.Printf("Response received %s\n", resp)
+	resp := <-recvChan
+	ack, err := da.ParseAck(resp[0])
+	if err != nil {
+		log.Fatalf("Unexpected response %s: %v", resp, err)
+	}
+	fmt.Printf("Response received: status=%s proof_id=%s", ack.StatusText, ack.ProofID)
+	if ack.Window != nil {
+		fmt.Printf(" window=%s..%s", ack.Window.Start, ack.Window.End)
+	}
+	if ack.Reason != "" {
+		fmt.Printf(" reason=%q", ack.Reason)
+	}
+	if ack.AggregateRoot != "" {
+		fmt.Printf(" aggregate_root=%s", ack.AggregateRoot)
+	}
+	if ack.RetryAfterSeconds > 0 {
+		fmt.Printf(" retry_after=%ds", ack.RetryAfterSeconds)
+	}
+	fmt.Println()
+
+	if ack.ProofID == "" {
+		return
+	}
+	// Ask where the proof is in its lifecycle
+	sendChan <- [][]byte{[]byte(da.StatusQueryLabel), []byte(ack.ProofID)}
+	resp = <-recvChan
+	var status da.ProofStatus
+	if err := json.Unmarshal(resp[0], &status); err != nil {
+		log.Fatalf("Unexpected status response %s: %v", resp, err)
+	}
+	fmt.Printf("Status: stage=%s aggregate_root=%s btc_tx=%s\n", status.Stage, status.AggregateRoot, status.BTCTxHash)
 }
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"log"
+	"os"
+
+	"github.com/Layer-Edge/bitcoin-da/config"
+	"github.com/Layer-Edge/bitcoin-da/da"
+	"github.com/consensys/gnark/backend/groth16"
+)
+
+var cfg = config.GetConfig()
+
+// Compiles the snark-aggregation circuit and writes its proving and verifying
+// keys. The setup randomness is generated locally, so the keys are only fit
+// for development; production keys need an MPC ceremony.
+func main() {
+	snark := cfg.SnarkAggregation
+	if snark.InnerVerifyingKey == "" || snark.ProvingKey == "" || snark.VerifyingKey == "" {
+		log.Fatal("snark-aggregation needs an inner-verifying-key, a proving-key and a verifying-key")
+	}
+
+	innerKey, err := da.ReadGroth16VerifyingKey(snark.InnerVerifyingKey)
+	if err != nil {
+		log.Fatal("Failed to read inner verifying key: ", err)
+	}
+
+	ccs, err := da.CompileSnarkAggregationCircuit(innerKey, snark.BatchSize)
+	if err != nil {
+		log.Fatal("Failed to compile aggregation circuit: ", err)
+	}
+	log.Printf("Running setup for %d proofs (%d constraints)", snark.BatchSize, ccs.GetNbConstraints())
+
+	pk, vk, err := groth16.Setup(ccs)
+	if err != nil {
+		log.Fatal("Setup failed: ", err)
+	}
+
+	writeKey(snark.ProvingKey, pk)
+	writeKey(snark.VerifyingKey, vk)
+	log.Printf("Wrote %s and %s", snark.ProvingKey, snark.VerifyingKey)
+}
+
+func writeKey(path string, key io.WriterTo) {
+	file, err := os.Create(path)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+	if _, err := key.WriteTo(writer); err != nil {
+		log.Fatalf("Failed to write %s: %v", path, err)
+	}
+	if err := writer.Flush(); err != nil {
+		log.Fatalf("Failed to write %s: %v", path, err)
+	}
+}
@@ -28,6 +28,7 @@ const (
 	ErrorTypeBlockchain ErrorType = "blockchain"
 	ErrorTypeProcessing ErrorType = "processing"
 	ErrorTypeSystem     ErrorType = "system"
+	ErrorTypeAuth       ErrorType = "authentication"
 	ErrorTypeUnknown    ErrorType = "unknown"
 )
 
@@ -241,6 +242,11 @@ func LogProcessingError(component string, message string, err error, context map
 	return GetErrorHandler().LogError(ErrorSeverityMedium, ErrorTypeProcessing, component, message, err, context)
 }
 
+// LogAuthError logs a rejected submitter signature or unknown submitter
+func LogAuthError(component string, message string, err error, context map[string]interface{}) ErrorInfo {
+	return GetErrorHandler().LogError(ErrorSeverityMedium, ErrorTypeAuth, component, message, err, context)
+}
+
 // LogSystemError logs a system-related error
 func LogSystemError(component string, message string, err error, context map[string]interface{}) ErrorInfo {
 	return GetErrorHandler().LogError(ErrorSeverityCritical, ErrorTypeSystem, component, message, err, context)